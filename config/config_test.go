@@ -588,6 +588,198 @@ endpoints:
 				assert.Contains(t, config.Endpoints[0].Path, "/very/long/path")
 			},
 		},
+		{
+			name: "config with TLS settings",
+			config: `---
+url: "https://staging.internal"
+tls:
+  ca-file: "certs/ca.pem"
+  cert-file: "certs/client.pem"
+  key-file: "certs/client-key.pem"
+  server-name: "api.internal"
+  min-version: "1.2"
+  insecure-skip-verify: true
+endpoints:
+  - path: "/health"
+    expected-status: 200`,
+			validate: func(t *testing.T, config *TestSuite) {
+				require.NotNil(t, config.TLS)
+				assert.Equal(t, "certs/ca.pem", config.TLS.CAFile)
+				assert.Equal(t, "certs/client.pem", config.TLS.CertFile)
+				assert.Equal(t, "certs/client-key.pem", config.TLS.KeyFile)
+				assert.Equal(t, "api.internal", config.TLS.ServerName)
+				assert.Equal(t, "1.2", config.TLS.MinVersion)
+				assert.True(t, config.TLS.InsecureSkipVerify)
+			},
+		},
+		{
+			name: "config with latency thresholds",
+			config: `---
+url: "https://example.com"
+endpoints:
+  - path: "/search"
+    expected-status: 200
+    timeout-ms: 5000
+    warn-latency-ms: 300
+    max-latency-ms: 800`,
+			validate: func(t *testing.T, config *TestSuite) {
+				endpoint := config.Endpoints[0]
+				require.NotNil(t, endpoint.Timeout)
+				require.NotNil(t, endpoint.WarnLatency)
+				require.NotNil(t, endpoint.MaxLatency)
+				assert.Equal(t, 5000, *endpoint.Timeout)
+				assert.Equal(t, 300, *endpoint.WarnLatency)
+				assert.Equal(t, 800, *endpoint.MaxLatency)
+			},
+		},
+		{
+			name: "config with names and tags",
+			config: `---
+name: "checkout"
+environment: "staging"
+url: "https://example.com"
+endpoints:
+  - name: "list users"
+    path: "/users"
+    expected-status: 200
+    tags: ["api", "critical"]
+  - path: "/health"
+    expected-status: 200`,
+			validate: func(t *testing.T, config *TestSuite) {
+				assert.Equal(t, "checkout", config.Name)
+				assert.Equal(t, "staging", config.Environment)
+				assert.Equal(t, "list users", config.Endpoints[0].DisplayName())
+				assert.Equal(t, []string{"api", "critical"}, config.Endpoints[0].Tags)
+				assert.Equal(t, "/health", config.Endpoints[1].DisplayName())
+				assert.Empty(t, config.Endpoints[1].Tags)
+			},
+		},
+		{
+			name: "config with exec plugin",
+			config: `---
+url: "https://example.com"
+endpoints:
+  - path: "/kafka/orders"
+    type: "exec"
+    exec:
+      command: "./plugins/kafka-topic"
+      args: ["--verbose"]
+      env:
+        BROKERS: "kafka:9092"
+      config:
+        topic: "orders"
+        partitions: 3
+  - path: "/health"
+    expected-status: 200`,
+			validate: func(t *testing.T, config *TestSuite) {
+				plugin := config.Endpoints[0]
+				assert.Equal(t, TypeExec, plugin.CheckType())
+				require.NotNil(t, plugin.Exec)
+				assert.Equal(t, "./plugins/kafka-topic", plugin.Exec.Command)
+				assert.Equal(t, []string{"--verbose"}, plugin.Exec.Args)
+				assert.Equal(t, map[string]string{"BROKERS": "kafka:9092"}, plugin.Exec.Env)
+				assert.Equal(t, map[string]any{"topic": "orders", "partitions": 3}, plugin.Exec.Config)
+				assert.Equal(t, TypeHTTP, config.Endpoints[1].CheckType())
+			},
+		},
+		{
+			name: "config with tcp check",
+			config: `---
+endpoints:
+  - path: "/redis"
+    type: "tcp"
+    timeout-ms: 2000
+    tcp:
+      address: "redis:6379"
+      connect-timeout-ms: 500
+      send: "PING\r\n"
+      expect: "+PONG"`,
+			validate: func(t *testing.T, config *TestSuite) {
+				endpoint := config.Endpoints[0]
+				assert.Equal(t, TypeTCP, endpoint.CheckType())
+				require.NotNil(t, endpoint.TCP)
+				assert.Equal(t, "redis:6379", endpoint.TCP.Address)
+				require.NotNil(t, endpoint.TCP.ConnectTimeout)
+				assert.Equal(t, 500, *endpoint.TCP.ConnectTimeout)
+				assert.Equal(t, "PING\r\n", endpoint.TCP.Send, "YAML escapes should be decoded")
+				assert.Equal(t, "+PONG", endpoint.TCP.Expect)
+			},
+		},
+		{
+			name: "config with grpc check",
+			config: `---
+endpoints:
+  - path: "/grpc/orders"
+    type: "grpc"
+    grpc:
+      address: "orders:50051"
+      service: "orders.v1.Orders"
+      tls:
+        ca-file: "/etc/ssl/internal-ca.pem"
+      metadata:
+        authorization: "Bearer token"
+      expected-status: "SERVING"`,
+			validate: func(t *testing.T, config *TestSuite) {
+				endpoint := config.Endpoints[0]
+				assert.Equal(t, TypeGRPC, endpoint.CheckType())
+				require.NotNil(t, endpoint.GRPC)
+				assert.Equal(t, "orders:50051", endpoint.GRPC.Address)
+				assert.Equal(t, "orders.v1.Orders", endpoint.GRPC.Service)
+				assert.False(t, endpoint.GRPC.Plaintext)
+				require.NotNil(t, endpoint.GRPC.TLS)
+				assert.Equal(t, "/etc/ssl/internal-ca.pem", endpoint.GRPC.TLS.CAFile)
+				assert.Equal(t, map[string]string{"authorization": "Bearer token"}, endpoint.GRPC.Metadata)
+				assert.Equal(t, "SERVING", endpoint.GRPC.ExpectedStatus)
+			},
+		},
+		{
+			name: "config with websocket check",
+			config: `---
+url: "https://example.com"
+endpoints:
+  - path: "/live"
+    type: "websocket"
+    websocket:
+      headers:
+        Authorization: "Bearer token"
+      send: '{"action": "subscribe"}'
+      json-field: "status"
+      json-value: "subscribed"
+      message-timeout-ms: 1500`,
+			validate: func(t *testing.T, config *TestSuite) {
+				endpoint := config.Endpoints[0]
+				assert.Equal(t, TypeWebSocket, endpoint.CheckType())
+				require.NotNil(t, endpoint.WebSocket)
+				assert.Equal(t, map[string]string{"Authorization": "Bearer token"}, endpoint.WebSocket.Headers)
+				assert.Equal(t, `{"action": "subscribe"}`, endpoint.WebSocket.Send)
+				assert.Equal(t, "status", endpoint.WebSocket.JSONField)
+				require.NotNil(t, endpoint.WebSocket.JSONValue)
+				assert.Equal(t, "subscribed", *endpoint.WebSocket.JSONValue)
+				require.NotNil(t, endpoint.WebSocket.MessageTimeout)
+				assert.Equal(t, 1500, *endpoint.WebSocket.MessageTimeout)
+			},
+		},
+		{
+			name: "config with event stream",
+			config: `---
+url: "https://example.com"
+endpoints:
+  - path: "/notifications"
+    expected-status: 200
+    stream:
+      event-type: "order"
+      data-contains: "created"
+      timeout-ms: 3000`,
+			validate: func(t *testing.T, config *TestSuite) {
+				endpoint := config.Endpoints[0]
+				assert.Equal(t, TypeHTTP, endpoint.CheckType())
+				require.NotNil(t, endpoint.Stream)
+				assert.Equal(t, "order", endpoint.Stream.EventType)
+				assert.Equal(t, "created", endpoint.Stream.DataContains)
+				require.NotNil(t, endpoint.Stream.Timeout)
+				assert.Equal(t, 3000, *endpoint.Stream.Timeout)
+			},
+		},
 		{
 			name: "YAML with null values",
 			config: `---
@@ -627,6 +819,57 @@ endpoints:
 	}
 }
 
+func TestTestSuite_FilterTags(t *testing.T) {
+	suite := &TestSuite{
+		URL: "https://example.com",
+		Endpoints: []Endpoint{
+			{Path: "/users", Tags: []string{"api", "critical"}},
+			{Path: "/health", Tags: []string{"infra"}},
+			{Path: "/untagged"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tags          []string
+		expectedPaths []string
+	}{
+		{
+			name:          "no tags keeps every endpoint",
+			tags:          nil,
+			expectedPaths: []string{"/users", "/health", "/untagged"},
+		},
+		{
+			name:          "single tag",
+			tags:          []string{"critical"},
+			expectedPaths: []string{"/users"},
+		},
+		{
+			name:          "any of several tags",
+			tags:          []string{"infra", "api"},
+			expectedPaths: []string{"/users", "/health"},
+		},
+		{
+			name:          "unknown tag",
+			tags:          []string{"billing"},
+			expectedPaths: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := suite.FilterTags(tt.tags)
+			paths := make([]string, 0, len(filtered.Endpoints))
+			for _, endpoint := range filtered.Endpoints {
+				paths = append(paths, endpoint.Path)
+			}
+			assert.Equal(t, tt.expectedPaths, paths)
+			assert.Equal(t, suite.URL, filtered.URL)
+			assert.Len(t, suite.Endpoints, 3, "original suite should be unchanged")
+		})
+	}
+}
+
 // Test the round-trip functionality (write then read)
 func TestConfig_RoundTrip(t *testing.T) {
 	originalConfig := &TestSuite{
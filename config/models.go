@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"slices"
 
 	"gopkg.in/yaml.v3"
 )
@@ -9,11 +10,26 @@ import (
 // TestSuite represents the top-level structure of the smoke test
 // suite configuration file.
 type TestSuite struct {
+	// Name is the name of the suite, used to label reports and metrics.
+	Name string `yaml:"name,omitempty"`
+
+	// Environment is the environment the suite targets, e.g. "staging".
+	Environment string `yaml:"environment,omitempty"`
+
 	// URL is the base URL of the target application.
 	URL string `yaml:"url"`
 
+	// Repeat is the number of times each endpoint is tested by default.
+	Repeat int `yaml:"repeat,omitempty"`
+
+	// TLS holds the TLS settings applied to every request in the suite.
+	TLS *TLSConfig `yaml:"tls,omitempty"`
+
 	// Endpoints is the list of endpoints to test.
 	Endpoints []Endpoint `yaml:"endpoints"`
+
+	// Notify is the list of webhooks notified of run outcomes.
+	Notify []NotifyTarget `yaml:"notify,omitempty"`
 }
 
 // Write writes the test suite configuration to a file.
@@ -25,14 +41,324 @@ func (tc *TestSuite) Write(filePath string) error {
 	return os.WriteFile(filePath, data, 0644)
 }
 
+// TLSConfig represents the TLS settings used when connecting to the
+// target application.
+type TLSConfig struct {
+	// CAFile is the path to a PEM bundle of CA certificates to trust.
+	CAFile string `yaml:"ca-file,omitempty"`
+
+	// CertFile is the path to the PEM client certificate for mutual TLS.
+	CertFile string `yaml:"cert-file,omitempty"`
+
+	// KeyFile is the path to the PEM private key of the client certificate.
+	KeyFile string `yaml:"key-file,omitempty"`
+
+	// ServerName overrides the hostname used for SNI and verification.
+	ServerName string `yaml:"server-name,omitempty"`
+
+	// MinVersion is the minimum accepted TLS version, e.g. "1.2".
+	MinVersion string `yaml:"min-version,omitempty"`
+
+	// InsecureSkipVerify disables server certificate verification.
+	InsecureSkipVerify bool `yaml:"insecure-skip-verify,omitempty"`
+}
+
+// NotifyTarget represents a webhook that is sent a message about the outcome
+// of a run.
+type NotifyTarget struct {
+	// Name identifies the target in logs. Defaults to the host of the URL.
+	Name string `yaml:"name,omitempty"`
+
+	// URL is the webhook URL the message is posted to.
+	URL string `yaml:"url"`
+
+	// Template selects a built-in payload, e.g. "slack" or "teams".
+	Template string `yaml:"template,omitempty"`
+
+	// Body is a Go template rendering the JSON payload, used instead of a
+	// built-in template.
+	Body string `yaml:"body,omitempty"`
+
+	// Headers are extra headers sent with the request.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// When selects the runs that are notified: "on-failure" (the default),
+	// "on-recovery" or "always".
+	When string `yaml:"when,omitempty"`
+}
+
+// Endpoint types selecting how an endpoint is checked.
+const (
+	TypeHTTP      string = "http"
+	TypeExec      string = "exec"
+	TypeTCP       string = "tcp"
+	TypeDNS       string = "dns"
+	TypeGRPC      string = "grpc"
+	TypeWebSocket string = "websocket"
+)
+
 // Endpoint represents a single endpoint to test.
 type Endpoint struct {
-	// Path is the path of the endpoint to test.
+	// Name is a human-readable name for the endpoint. Defaults to the path.
+	Name string `yaml:"name,omitempty"`
+
+	// Type selects how the endpoint is checked. Defaults to "http".
+	Type string `yaml:"type,omitempty"`
+
+	// Path is the path of the endpoint to test. Endpoints of other types
+	// are identified by their path in reports and history, so it is
+	// required and unique within the suite.
 	Path string `yaml:"path"`
 
+	// Tags are free-form labels used to group and filter endpoints.
+	Tags []string `yaml:"tags,omitempty"`
+
 	// ExpectedStatus is the expected HTTP status code of the response.
 	ExpectedStatus int `yaml:"expected-status"`
 
 	// Timeout is the timeout for the test.
 	Timeout *int `yaml:"timeout-ms,omitempty"`
+
+	// MaxLatency is the latency above which the endpoint breaches its SLO.
+	MaxLatency *int `yaml:"max-latency-ms,omitempty"`
+
+	// WarnLatency is the latency above which a warning is reported.
+	WarnLatency *int `yaml:"warn-latency-ms,omitempty"`
+
+	// Samples is the number of times the endpoint is tested, overriding
+	// the suite-level repeat count.
+	Samples int `yaml:"samples,omitempty"`
+
+	// Schedule is a cron expression controlling how often the endpoint is
+	// checked in monitor mode, overriding the monitor interval.
+	Schedule string `yaml:"schedule,omitempty"`
+
+	// LatencyPercentile selects the aggregate latency that the latency
+	// thresholds apply to when an endpoint is sampled more than once.
+	LatencyPercentile string `yaml:"latency-percentile,omitempty"`
+
+	// Certificate enables TLS certificate assertions for HTTPS targets.
+	Certificate *CertificateCheck `yaml:"certificate,omitempty"`
+
+	// Quarantine reports failures of the endpoint without failing the run.
+	Quarantine bool `yaml:"quarantine,omitempty"`
+
+	// Mock is the canned response served for the endpoint in mock mode.
+	Mock *MockResponse `yaml:"mock,omitempty"`
+
+	// Stream treats the response of an HTTP endpoint as a Server-Sent
+	// Events stream.
+	Stream *StreamCheck `yaml:"stream,omitempty"`
+
+	// Exec configures the plugin run by endpoints of type "exec".
+	Exec *ExecCheck `yaml:"exec,omitempty"`
+
+	// TCP configures the connection made by endpoints of type "tcp".
+	TCP *TCPCheck `yaml:"tcp,omitempty"`
+
+	// DNS configures the lookup made by endpoints of type "dns".
+	DNS *DNSCheck `yaml:"dns,omitempty"`
+
+	// GRPC configures the health check made by endpoints of type "grpc".
+	GRPC *GRPCCheck `yaml:"grpc,omitempty"`
+
+	// WebSocket configures the exchange made by endpoints of type
+	// "websocket". The path is resolved against the suite URL, using ws://
+	// or wss:// for http:// or https:// URLs.
+	WebSocket *WebSocketCheck `yaml:"websocket,omitempty"`
+}
+
+// TCPCheck represents a TCP connection to a port, optionally followed by a
+// banner exchange.
+type TCPCheck struct {
+	// Address is the host:port to connect to.
+	Address string `yaml:"address"`
+
+	// ConnectTimeout is the timeout for establishing the connection, in
+	// milliseconds. The endpoint timeout bounds the whole check.
+	ConnectTimeout *int `yaml:"connect-timeout-ms,omitempty"`
+
+	// Send is written to the connection once it is established.
+	Send string `yaml:"send,omitempty"`
+
+	// Expect is the text that must be received for the check to pass.
+	Expect string `yaml:"expect,omitempty"`
+}
+
+// ExecCheck represents an external plugin executable that checks an endpoint.
+// The plugin receives the endpoint as JSON on stdin and writes its result as
+// JSON to stdout.
+type ExecCheck struct {
+	// Command is the path of the executable.
+	Command string `yaml:"command"`
+
+	// Args are the arguments passed to the executable.
+	Args []string `yaml:"args,omitempty"`
+
+	// Env holds extra environment variables for the executable.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Config is free-form plugin configuration passed on stdin.
+	Config map[string]any `yaml:"config,omitempty"`
+}
+
+// MockResponse represents the canned response served for an endpoint by the
+// mock server. The status code is always the expected status of the endpoint.
+type MockResponse struct {
+	// Body is the response body.
+	Body string `yaml:"body,omitempty"`
+
+	// Headers are the response headers.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Latency is the artificial delay before responding, in milliseconds.
+	Latency *int `yaml:"latency-ms,omitempty"`
+}
+
+// DNSCheck represents a DNS lookup and the assertions made on its answers.
+type DNSCheck struct {
+	// Name is the domain name to resolve.
+	Name string `yaml:"name"`
+
+	// RecordType is the type of record to resolve: A (the default), AAAA,
+	// CNAME, TXT or SRV.
+	RecordType string `yaml:"record-type,omitempty"`
+
+	// Server is the host[:port] of the DNS server to query. Defaults to the
+	// system resolver.
+	Server string `yaml:"server,omitempty"`
+
+	// Expect lists answers that must all be present. SRV answers are
+	// written as target:port.
+	Expect []string `yaml:"expect,omitempty"`
+
+	// MinRecords is the minimum number of answers.
+	MinRecords int `yaml:"min-records,omitempty"`
+
+	// CNAME is the canonical name the name must resolve through.
+	CNAME string `yaml:"cname,omitempty"`
+}
+
+// GRPCCheck represents a call to the standard gRPC health checking service,
+// grpc.health.v1.Health/Check.
+type GRPCCheck struct {
+	// Address is the host:port of the gRPC server.
+	Address string `yaml:"address"`
+
+	// Service is the name of the service to check. Defaults to the overall
+	// health of the server.
+	Service string `yaml:"service,omitempty"`
+
+	// Plaintext connects without TLS.
+	Plaintext bool `yaml:"plaintext,omitempty"`
+
+	// TLS holds the TLS settings of the connection. Defaults to verifying
+	// the server against the system roots.
+	TLS *TLSConfig `yaml:"tls,omitempty"`
+
+	// Metadata holds the metadata headers sent with the call.
+	Metadata map[string]string `yaml:"metadata,omitempty"`
+
+	// ExpectedStatus is the expected serving status. Defaults to "SERVING".
+	ExpectedStatus string `yaml:"expected-status,omitempty"`
+}
+
+// WebSocketCheck represents a WebSocket handshake, optionally followed by a
+// message exchange. Without assertions, a check that sends a message passes
+// once the first message is received.
+type WebSocketCheck struct {
+	// Headers are extra headers sent with the handshake request.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Send is a text message sent once the connection is established.
+	Send string `yaml:"send,omitempty"`
+
+	// Contains is text the first received message must contain.
+	Contains string `yaml:"contains,omitempty"`
+
+	// Regex is a regular expression the first received message must match.
+	Regex string `yaml:"regex,omitempty"`
+
+	// JSONField is the dot-separated path of a field that must be present
+	// in the first received message, e.g. "data.status".
+	JSONField string `yaml:"json-field,omitempty"`
+
+	// JSONValue is the expected value of the JSON field, compared as text.
+	JSONValue *string `yaml:"json-value,omitempty"`
+
+	// MessageTimeout is how long to wait for the first message, in
+	// milliseconds. The endpoint timeout bounds the whole check.
+	MessageTimeout *int `yaml:"message-timeout-ms,omitempty"`
+}
+
+// StreamCheck represents the events read from a Server-Sent Events stream
+// before the connection is closed. With an event type or data to match, the
+// check waits for a matching event; otherwise it waits for a number of events.
+type StreamCheck struct {
+	// Events is the number of events to receive. Defaults to 1.
+	Events int `yaml:"events,omitempty"`
+
+	// EventType is the type of the event to wait for.
+	EventType string `yaml:"event-type,omitempty"`
+
+	// DataContains is text the data of the event to wait for must contain.
+	DataContains string `yaml:"data-contains,omitempty"`
+
+	// Timeout is how long to read events for, in milliseconds. The endpoint
+	// timeout bounds the whole request. Streams without either timeout are
+	// read for at most 30 seconds.
+	Timeout *int `yaml:"timeout-ms,omitempty"`
+}
+
+// CertificateCheck represents the assertions made on the TLS certificate
+// presented by an HTTPS endpoint. When set, the hostname and chain of the
+// certificate are always verified.
+type CertificateCheck struct {
+	// WarnDays is the number of days before expiry at which to warn.
+	WarnDays int `yaml:"warn-days,omitempty"`
+
+	// FailDays is the number of days before expiry at which to fail.
+	FailDays int `yaml:"fail-days,omitempty"`
+}
+
+// DisplayName returns the name of the endpoint, falling back to its path.
+func (e *Endpoint) DisplayName() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return e.Path
+}
+
+// CheckType returns the type of the endpoint, defaulting to "http".
+func (e *Endpoint) CheckType() string {
+	if e.Type == "" {
+		return TypeHTTP
+	}
+	return e.Type
+}
+
+// HasAnyTag reports whether the endpoint has at least one of the given tags.
+func (e *Endpoint) HasAnyTag(tags []string) bool {
+	for _, tag := range tags {
+		if slices.Contains(e.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterTags returns a copy of the suite containing only the endpoints with
+// at least one of the given tags. An empty tag list keeps every endpoint.
+func (tc *TestSuite) FilterTags(tags []string) *TestSuite {
+	filtered := *tc
+	if len(tags) == 0 {
+		return &filtered
+	}
+	filtered.Endpoints = make([]Endpoint, 0, len(tc.Endpoints))
+	for _, endpoint := range tc.Endpoints {
+		if endpoint.HasAnyTag(tags) {
+			filtered.Endpoints = append(filtered.Endpoints, endpoint)
+		}
+	}
+	return &filtered
 }
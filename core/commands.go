@@ -1,8 +1,18 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -10,12 +20,34 @@ import (
 
 	"github.com/jgfranco17/dev-tooling-go/logging"
 	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/diff"
+	"github.com/jgfranco17/smokesweep/history"
+	"github.com/jgfranco17/smokesweep/metrics"
+	"github.com/jgfranco17/smokesweep/mock"
+	"github.com/jgfranco17/smokesweep/monitor"
+	"github.com/jgfranco17/smokesweep/notify"
+	"github.com/jgfranco17/smokesweep/outputs"
 	"github.com/jgfranco17/smokesweep/runner"
+	"github.com/jgfranco17/smokesweep/server"
 )
 
 func GetRunCommand() *cobra.Command {
 	var configFilePath string
+	var reportFilePath string
 	var failFast bool
+	var strictLatency bool
+	var repeat int
+	var metricsFilePath string
+	var pushgatewayURL string
+	var pushJob string
+	var baselineFilePath string
+	var latencyRegression float64
+	var failOnRegression bool
+	var historyDir string
+	var noHistory bool
+	var flakyThreshold float64
+	var flakyWindow int
+	var quarantineFlaky bool
 
 	runCmd := &cobra.Command{
 		Use:          "run",
@@ -26,38 +58,591 @@ func GetRunCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := logging.FromContext(cmd.Context())
 
-			file, err := os.Open(configFilePath)
+			testConfigs, err := loadTestSuite(configFilePath)
 			if err != nil {
-				return fmt.Errorf("error opening config file: %w", err)
-			}
-			defer file.Close()
-
-			testConfigs, err := config.Load(file)
-			if err != nil {
-				return fmt.Errorf("error loading config file: %w", err)
+				return err
 			}
 			logger.WithFields(
 				logrus.Fields{
 					"config": configFilePath,
 				},
 			).Debug("Config file loaded successfully")
+			if repeat > 0 {
+				testConfigs.Repeat = repeat
+			}
+			var previous map[string][]bool
+			if flakyWindow > 0 {
+				previous, err = loadPreviousOutcomes(historyDir, testConfigs, flakyWindow)
+				if err != nil {
+					logger.WithError(err).Warn("Failed to read run history")
+				}
+			}
+			if quarantineFlaky {
+				for i, endpoint := range testConfigs.Endpoints {
+					if runner.IsFlaky(runner.HistoryFlakiness(previous[endpoint.Path]), flakyThreshold) {
+						testConfigs.Endpoints[i].Quarantine = true
+					}
+				}
+			}
+			notifier, err := newNotifier(historyDir, testConfigs)
+			if err != nil {
+				return err
+			}
 			report, err := runner.Execute(cmd.Context(), testConfigs, failFast)
 			if err != nil {
 				return fmt.Errorf("error running tests: %w", err)
 			}
+			report.DetectFlaky(previous, flakyThreshold)
+			if quarantineFlaky {
+				report.QuarantineFlaky()
+			}
+			if baselineFilePath != "" {
+				baseline, err := loadReportFile(baselineFilePath)
+				if err != nil {
+					return err
+				}
+				report.CompareBaseline(baseline, latencyRegression)
+			}
+			var latencyErr, regressionErr error
+			if strictLatency {
+				latencyErr = report.EnforceLatency()
+			}
+			if failOnRegression {
+				regressionErr = report.EnforceBaseline()
+			}
+			if !noHistory {
+				if err := recordHistory(historyDir, testConfigs, report); err != nil {
+					logger.WithError(err).Warn("Failed to record run history")
+				}
+			}
+			if notifier != nil {
+				if err := notifier.Notify(cmd.Context(), report); err != nil {
+					logger.WithError(err).Warn("Failed to send notifications")
+				}
+			}
+			if reportFilePath != "" {
+				if err := writeReportFile(reportFilePath, report); err != nil {
+					return err
+				}
+			}
+			if metricsFilePath != "" || pushgatewayURL != "" {
+				collector := metrics.NewCollector(testConfigs)
+				collector.Observe(report)
+				if metricsFilePath != "" {
+					if err := collector.WriteTextfile(metricsFilePath); err != nil {
+						return err
+					}
+				}
+				if pushgatewayURL != "" {
+					if err := collector.Push(cmd.Context(), pushgatewayURL, pushJob); err != nil {
+						return err
+					}
+				}
+			}
 			if err := report.SummarizeResults(); err != nil {
 				return fmt.Errorf("error summarizing test results: %w", err)
 			}
+			if err := errors.Join(latencyErr, regressionErr); err != nil {
+				return err
+			}
+			if !report.Passed() {
+				return fmt.Errorf("%d endpoint(s) failed", failedEndpoints(report))
+			}
 			return nil
 		},
 	}
 	runCmd.Flags().StringVarP(&configFilePath, "config-file", "f", runner.DefaultConfigFile, "Path to YAML config file")
+	runCmd.Flags().StringVarP(&reportFilePath, "report", "o", "", "Write a JSON report of the results to this path")
+	runCmd.Flags().StringVar(&metricsFilePath, "metrics-file", "", "Write Prometheus metrics of the results to this path")
+	runCmd.Flags().StringVar(&pushgatewayURL, "pushgateway", "", "Push Prometheus metrics of the results to this Pushgateway URL")
+	runCmd.Flags().StringVar(&pushJob, "push-job", metrics.DefaultPushJob, "Job name used when pushing metrics")
 	runCmd.Flags().BoolVarP(&failFast, "fail-fast", "x", false, "Stop executing tests on the first failure")
+	runCmd.Flags().IntVarP(&repeat, "repeat", "n", 0, "Number of times to test each endpoint, overriding the config file")
+	runCmd.Flags().BoolVar(&strictLatency, "strict-latency", false, "Fail the run if any endpoint exceeds its max latency")
+	runCmd.Flags().StringVar(&baselineFilePath, "baseline", "", "Compare the results against a JSON report from a previous run")
+	runCmd.Flags().Float64Var(&latencyRegression, "latency-regression", runner.DefaultLatencyRegression, "Report endpoints that slowed down by more than this percentage since the baseline")
+	runCmd.Flags().BoolVar(&failOnRegression, "fail-on-regression", false, "Fail the run if any endpoint regressed since the baseline")
+	runCmd.Flags().StringVar(&historyDir, "history-dir", "", "Directory of the run history store (default ~/.local/share/smokesweep)")
+	runCmd.Flags().BoolVar(&noHistory, "no-history", false, "Do not record the run in the history store")
+	runCmd.Flags().Float64Var(&flakyThreshold, "flaky-threshold", runner.DefaultFlakyThreshold, "Flakiness score from 0 to 1 at which an endpoint is reported as flaky")
+	runCmd.Flags().IntVar(&flakyWindow, "flaky-window", 10, "Number of previous runs from the history considered for flakiness, 0 to only use repeated samples")
+	runCmd.Flags().BoolVar(&quarantineFlaky, "quarantine-flaky", false, "Report flaky endpoints without letting them fail the run")
 	return runCmd
 }
 
+func GetMonitorCommand() *cobra.Command {
+	var configFilePath string
+	var interval time.Duration
+	var metricsAddr string
+
+	cmd := &cobra.Command{
+		Use:          "monitor",
+		Short:        "Continuously monitor the smoke test suite",
+		Long:         "Re-run the smoke tests on an interval, reporting only when endpoints go down or recover.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.FromContext(cmd.Context())
+			testConfigs, err := loadTestSuite(configFilePath)
+			if err != nil {
+				return err
+			}
+			m, err := monitor.New(testConfigs, interval)
+			if err != nil {
+				return fmt.Errorf("error configuring monitor: %w", err)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if len(testConfigs.Notify) > 0 {
+				notifier, err := notify.New(testConfigs)
+				if err != nil {
+					return fmt.Errorf("error configuring notifications: %w", err)
+				}
+				// The notifier is given the latest state of the whole suite,
+				// and only when endpoints go down or recover.
+				m.OnChange(func(report runner.TestReport) {
+					if err := notifier.Notify(ctx, report); err != nil {
+						logger.WithError(err).Warn("Failed to send notifications")
+					}
+				})
+			}
+
+			if metricsAddr != "" {
+				collector := metrics.NewCollector(testConfigs)
+				m.OnRun(collector.Observe)
+				server, err := serveMetrics(ctx, metricsAddr, collector)
+				if err != nil {
+					return err
+				}
+				defer server.Close()
+				logger.WithField("address", server.Addr).Info("Serving metrics")
+			}
+
+			logger.WithFields(logrus.Fields{
+				"config":   configFilePath,
+				"interval": interval,
+			}).Info("Starting monitor")
+			return m.Run(ctx)
+		},
+	}
+	cmd.Flags().StringVarP(&configFilePath, "config-file", "f", runner.DefaultConfigFile, "Path to YAML config file")
+	cmd.Flags().DurationVarP(&interval, "interval", "i", time.Minute, "Interval between runs for endpoints without a schedule")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Address to serve Prometheus metrics on, e.g. :9090")
+	return cmd
+}
+
+// serveMetrics starts an HTTP server exposing the collector on /metrics.
+func serveMetrics(ctx context.Context, addr string, collector *metrics.Collector) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on %s: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", collector.Handler())
+	server := &http.Server{
+		Addr:              listener.Addr().String(),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.FromContext(ctx).WithError(err).Error("Metrics server stopped")
+		}
+	}()
+	return server, nil
+}
+
+func GetServeCommand() *cobra.Command {
+	var configFilePaths []string
+	var addr string
+	var workers int
+	var queueSize int
+
+	cmd := &cobra.Command{
+		Use:          "serve",
+		Short:        "Serve an HTTP API for triggering runs",
+		Long:         "Start an HTTP server that runs the loaded smoke test suites on request.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.FromContext(cmd.Context())
+			suites := make([]*config.TestSuite, 0, len(configFilePaths))
+			for _, filePath := range configFilePaths {
+				suite, err := loadTestSuite(filePath)
+				if err != nil {
+					return err
+				}
+				if suite.Name == "" {
+					suite.Name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+				}
+				suites = append(suites, suite)
+			}
+			apiServer, err := server.New(suites, workers, queueSize)
+			if err != nil {
+				return fmt.Errorf("error configuring server: %w", err)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			apiServer.Start(ctx)
+
+			httpServer := &http.Server{
+				Addr:              addr,
+				Handler:           apiServer.Handler(),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = httpServer.Shutdown(shutdownCtx)
+			}()
+			logger.WithFields(logrus.Fields{
+				"address": addr,
+				"suites":  len(suites),
+			}).Info("Starting API server")
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("error serving API: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVarP(&configFilePaths, "config-file", "f", []string{runner.DefaultConfigFile}, "Path to YAML config file, may be repeated")
+	cmd.Flags().StringVar(&addr, "addr", ":8080", "Address to listen on")
+	cmd.Flags().IntVar(&workers, "workers", 1, "Number of runs executed concurrently")
+	cmd.Flags().IntVar(&queueSize, "queue-size", 10, "Maximum number of runs waiting to execute")
+	return cmd
+}
+
+func GetMockCommand() *cobra.Command {
+	var configFilePath string
+	var addr string
+
+	cmd := &cobra.Command{
+		Use:          "mock",
+		Short:        "Serve a mock of the smoke test target",
+		Long:         "Start a local HTTP server answering each configured endpoint with its expected status and canned response.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.FromContext(cmd.Context())
+			testConfigs, err := loadTestSuite(configFilePath)
+			if err != nil {
+				return err
+			}
+			handler, err := mock.NewHandler(testConfigs)
+			if err != nil {
+				return fmt.Errorf("error configuring mock server: %w", err)
+			}
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("error listening on %s: %w", addr, err)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			mockServer := &http.Server{
+				Handler:           handler,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = mockServer.Shutdown(shutdownCtx)
+			}()
+			logger.WithFields(logrus.Fields{
+				"config":    configFilePath,
+				"endpoints": len(testConfigs.Endpoints),
+			}).Info("Starting mock server")
+			outputs.PrintColoredMessage("cyan", "MOCK", "Serving %d endpoints on http://%s", len(testConfigs.Endpoints), listener.Addr())
+			if err := mockServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("error serving mock: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&configFilePath, "config-file", "f", runner.DefaultConfigFile, "Path to YAML config file")
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8081", "Address to listen on")
+	return cmd
+}
+
+func GetDiffCommand() *cobra.Command {
+	var configFilePath string
+	var baseURL string
+	var compareURL string
+	var opts diff.Options
+
+	cmd := &cobra.Command{
+		Use:          "diff",
+		Short:        "Compare two environments",
+		Long:         "Run the smoke tests against two base URLs and report endpoints whose responses differ.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := logging.FromContext(cmd.Context())
+			if baseURL == "" || compareURL == "" {
+				return fmt.Errorf("both --base-url and --compare-url are required")
+			}
+			testConfigs, err := loadTestSuite(configFilePath)
+			if err != nil {
+				return err
+			}
+
+			baseSuite, compareSuite := *testConfigs, *testConfigs
+			baseSuite.URL, compareSuite.URL = baseURL, compareURL
+			baseReport, err := runner.Execute(cmd.Context(), &baseSuite, false)
+			if err != nil {
+				return fmt.Errorf("error running tests against %s: %w", baseURL, err)
+			}
+			compareReport, err := runner.Execute(cmd.Context(), &compareSuite, false)
+			if err != nil {
+				return fmt.Errorf("error running tests against %s: %w", compareURL, err)
+			}
+			logger.WithFields(logrus.Fields{
+				"base":    baseURL,
+				"compare": compareURL,
+			}).Debug("Both environments tested")
+
+			differences := diff.Compare(baseReport, compareReport, opts)
+			diverged := make(map[string]bool)
+			for _, difference := range differences {
+				diverged[difference.Path] = true
+				outputs.PrintColoredMessage("red", "DIFF", "%s [%s] %s", difference.Path, difference.Kind, difference.Detail)
+			}
+			for _, endpoint := range testConfigs.Endpoints {
+				if !diverged[endpoint.Path] {
+					outputs.PrintColoredMessage("green", "SAME", "%s", endpoint.Path)
+				}
+			}
+			if len(diverged) > 0 {
+				return fmt.Errorf("%d endpoint(s) diverged", len(diverged))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&configFilePath, "config-file", "f", runner.DefaultConfigFile, "Path to YAML config file")
+	cmd.Flags().StringVar(&baseURL, "base-url", "", "Base URL of the reference environment")
+	cmd.Flags().StringVar(&compareURL, "compare-url", "", "Base URL of the environment to compare against the reference")
+	cmd.Flags().StringSliceVar(&opts.IgnoreFields, "ignore-field", nil, "JSON field to ignore in body comparisons, by name or dotted path; may be repeated")
+	cmd.Flags().Float64Var(&opts.LatencyDeltaPercent, "latency-delta", 50, "Report latency differences larger than this percentage")
+	cmd.Flags().DurationVar(&opts.MinLatencyDelta, "min-latency-delta", 100*time.Millisecond, "Ignore latency differences smaller than this duration")
+	return cmd
+}
+
+func loadTestSuite(filePath string) (*config.TestSuite, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening config file: %w", err)
+	}
+	defer file.Close()
+
+	testConfigs, err := config.Load(file)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config file: %w", err)
+	}
+	return testConfigs, nil
+}
+
+func writeReportFile(filePath string, report runner.TestReport) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("error creating report file: %w", err)
+	}
+	defer file.Close()
+	return report.WriteJSON(file)
+}
+
+func loadReportFile(filePath string) (*runner.TestReport, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening baseline report: %w", err)
+	}
+	defer file.Close()
+	return runner.ReadReport(file)
+}
+
+// failedEndpoints returns the number of endpoints of the report that failed
+// or could not be reached, excluding quarantined ones.
+func failedEndpoints(report runner.TestReport) int {
+	failed := 0
+	for _, result := range slices.Concat(report.Results, report.Unreachable) {
+		if !result.Passed && !result.Quarantined {
+			failed++
+		}
+	}
+	return failed
+}
+
+// recordHistory appends the report to the history store in the directory.
+func recordHistory(dir string, suite *config.TestSuite, report runner.TestReport) error {
+	store, err := history.Open(dir)
+	if err != nil {
+		return err
+	}
+	return store.Append(history.Entry{
+		Suite:       suite.Name,
+		Environment: suite.Environment,
+		URL:         suite.URL,
+		Report:      report,
+	})
+}
+
+// loadPreviousOutcomes returns the outcomes of the last runs of each endpoint
+// of the suite against its base URL recorded in the history store in the
+// directory.
+func loadPreviousOutcomes(dir string, suite *config.TestSuite, window int) (map[string][]bool, error) {
+	store, err := history.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := store.List(history.Query{Suite: suite.Name, URL: suite.URL})
+	if err != nil {
+		return nil, err
+	}
+	outcomes := make(map[string][]bool, len(suite.Endpoints))
+	for _, endpoint := range suite.Endpoints {
+		outcomes[endpoint.Path] = history.Outcomes(entries, endpoint.Path, window)
+	}
+	return outcomes, nil
+}
+
+// newNotifier returns a notifier for the suite, or nil if it has no notify
+// targets. The outcome of the last recorded run of the suite against its base
+// URL is used to detect recoveries.
+func newNotifier(historyDir string, suite *config.TestSuite) (*notify.Notifier, error) {
+	if len(suite.Notify) == 0 {
+		return nil, nil
+	}
+	notifier, err := notify.New(suite)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring notifications: %w", err)
+	}
+	store, err := history.Open(historyDir)
+	if err != nil {
+		return notifier, nil
+	}
+	entries, err := store.List(history.Query{Suite: suite.Name, URL: suite.URL})
+	if err == nil && len(entries) > 0 {
+		notifier.SetLastOutcome(entries[len(entries)-1].Failed())
+	}
+	return notifier, nil
+}
+
+func GetHistoryCommand() *cobra.Command {
+	var historyDir string
+	var suite string
+	var since time.Duration
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:          "history",
+		Short:        "List recorded runs",
+		Long:         "List the runs recorded in the local history store, most recent first.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := history.Open(historyDir)
+			if err != nil {
+				return err
+			}
+			entries, err := store.List(history.Query{Suite: suite, Since: time.Now().Add(-since)})
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				outputs.PrintWarn("No runs recorded in the last %s", since)
+				return nil
+			}
+			slices.Reverse(entries)
+			if limit > 0 && len(entries) > limit {
+				entries = entries[:limit]
+			}
+			rows := make([][]string, len(entries))
+			for i, entry := range entries {
+				rows[i] = []string{
+					entry.Report.Timestamp.Local().Format(time.DateTime),
+					entry.Suite,
+					entry.Environment,
+					entry.URL,
+					fmt.Sprintf("%d/%d", entry.Passed(), entry.Total()),
+				}
+			}
+			outputs.PrintTable([]string{"TIME", "SUITE", "ENVIRONMENT", "URL", "PASSED"}, rows)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&historyDir, "history-dir", "", "Directory of the run history store (default ~/.local/share/smokesweep)")
+	cmd.Flags().StringVarP(&suite, "suite", "s", "", "Only list runs of the suite with this name")
+	cmd.Flags().DurationVar(&since, "since", 7*24*time.Hour, "Only list runs within this duration")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of runs to list, 0 for no limit")
+	return cmd
+}
+
+func GetTrendCommand() *cobra.Command {
+	var historyDir string
+	var suite string
+	var since time.Duration
+	var bucket time.Duration
+
+	cmd := &cobra.Command{
+		Use:          "trend <endpoint>",
+		Short:        "Show the trend of an endpoint",
+		Long:         "Show the pass rate and latency percentiles of an endpoint path over the recorded run history.",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := history.Open(historyDir)
+			if err != nil {
+				return err
+			}
+			start := time.Now().Add(-since)
+			entries, err := store.List(history.Query{Suite: suite, Since: start})
+			if err != nil {
+				return err
+			}
+			buckets, err := history.Trend(entries, args[0], start, bucket)
+			if err != nil {
+				return err
+			}
+			if len(buckets) == 0 {
+				outputs.PrintWarn("No runs of %s recorded in the last %s", args[0], since)
+				return nil
+			}
+			overall, _ := history.Summarize(entries, args[0], start)
+
+			ms := func(d time.Duration) string {
+				return fmt.Sprintf("%vms", d.Milliseconds())
+			}
+			row := func(label string, stats runner.LatencyStats) []string {
+				return []string{
+					label,
+					strconv.Itoa(stats.Samples),
+					fmt.Sprintf("%.0f%%", stats.SuccessRatio()*100),
+					ms(stats.P50), ms(stats.P90), ms(stats.P99),
+				}
+			}
+			rows := make([][]string, 0, len(buckets)+1)
+			for _, b := range buckets {
+				rows = append(rows, row(b.Start.Local().Format(time.DateTime), b.Stats))
+			}
+			rows = append(rows, row("TOTAL", overall))
+			outputs.PrintTable([]string{"PERIOD", "RUNS", "PASS RATE", "P50", "P90", "P99"}, rows)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&historyDir, "history-dir", "", "Directory of the run history store (default ~/.local/share/smokesweep)")
+	cmd.Flags().StringVarP(&suite, "suite", "s", "", "Only include runs of the suite with this name")
+	cmd.Flags().DurationVar(&since, "since", 7*24*time.Hour, "Time window to aggregate over")
+	cmd.Flags().DurationVar(&bucket, "bucket", 24*time.Hour, "Width of each period in the trend")
+	return cmd
+}
+
 func GetPingCommand() *cobra.Command {
 	var timeout time.Duration
+	var tlsConf config.TLSConfig
 	cmd := &cobra.Command{
 		Use:          "ping",
 		Short:        "Ping a target URL",
@@ -67,7 +652,7 @@ func GetPingCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := logging.FromContext(cmd.Context())
 			target := args[0]
-			if err := runner.PingURL(cmd.Context(), target, timeout); err != nil {
+			if err := runner.PingURL(cmd.Context(), target, timeout, &tlsConf); err != nil {
 				logger.WithFields(
 					logrus.Fields{
 						"target": target,
@@ -81,5 +666,11 @@ func GetPingCommand() *cobra.Command {
 	}
 	defaultTimeout := 5 * time.Second
 	cmd.Flags().DurationVarP(&timeout, "timeout", "t", defaultTimeout, "Timeout duration (in seconds) for the ping request, default is 5s")
+	cmd.Flags().StringVar(&tlsConf.CAFile, "ca-file", "", "Path to a PEM bundle of CA certificates to trust")
+	cmd.Flags().StringVar(&tlsConf.CertFile, "cert-file", "", "Path to a PEM client certificate for mutual TLS")
+	cmd.Flags().StringVar(&tlsConf.KeyFile, "key-file", "", "Path to the PEM private key of the client certificate")
+	cmd.Flags().StringVar(&tlsConf.ServerName, "server-name", "", "Override the server name used for TLS verification")
+	cmd.Flags().StringVar(&tlsConf.MinVersion, "tls-min-version", "", "Minimum TLS version to accept (1.0, 1.1, 1.2, 1.3)")
+	cmd.Flags().BoolVarP(&tlsConf.InsecureSkipVerify, "insecure", "k", false, "Skip TLS certificate verification (not recommended)")
 	return cmd
 }
@@ -8,10 +8,14 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/jgfranco17/dev-tooling-go/logging"
 	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/history"
+	"github.com/jgfranco17/smokesweep/runner"
 	"github.com/sirupsen/logrus"
 
 	"github.com/spf13/cobra"
@@ -27,6 +31,20 @@ type CliRunResult struct {
 	Error       error
 }
 
+// TestMain points the history store at a temporary directory so that test
+// runs do not pollute the user's run history.
+func TestMain(m *testing.M) {
+	dataHome, err := os.MkdirTemp("", "smokesweep-data-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create data directory: %v\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("XDG_DATA_HOME", dataHome)
+	code := m.Run()
+	os.RemoveAll(dataHome)
+	os.Exit(code)
+}
+
 func createTempDir(t *testing.T) string {
 	t.Helper()
 	// Create a temporary directory
@@ -86,6 +104,130 @@ func TestRunCommandSuccess(t *testing.T) {
 	assert.NoError(t, output.Error, "Unexpected error while executing run command")
 }
 
+func TestRunCommandWritesReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	mockConfig := config.TestSuite{
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/users", ExpectedStatus: 200}},
+	}
+	temp := t.TempDir()
+	mockConfigFilePath := filepath.Join(temp, "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+	reportFilePath := filepath.Join(temp, "report.json")
+
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--report", reportFilePath)
+	assert.NoError(t, output.Error)
+
+	data, err := os.ReadFile(reportFilePath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"target": "`+server.URL+`/users"`)
+	assert.Contains(t, string(data), `"timings"`)
+}
+
+func TestRunCommandStrictLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	maxLatency := 10
+	mockConfig := config.TestSuite{
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/slow", ExpectedStatus: 200, MaxLatency: &maxLatency}},
+	}
+	mockConfigFilePath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath)
+	assert.NoError(t, output.Error, "latency breaches should not fail the run by default")
+
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--strict-latency")
+	assert.ErrorContains(t, output.Error, "exceeded their max latency")
+}
+
+func TestRunCommandBaseline(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+	mockConfig := config.TestSuite{
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/users", ExpectedStatus: 200}},
+	}
+	temp := t.TempDir()
+	mockConfigFilePath := filepath.Join(temp, "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+	baselineFilePath := filepath.Join(temp, "baseline.json")
+
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--report", baselineFilePath)
+	assert.NoError(t, output.Error)
+
+	status.Store(http.StatusInternalServerError)
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--baseline", baselineFilePath)
+	assert.EqualError(t, output.Error, "1 endpoint(s) failed", "regressions should only fail the run through the failing endpoint by default")
+
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--baseline", baselineFilePath, "--fail-on-regression")
+	assert.ErrorContains(t, output.Error, "1 regression(s) since baseline")
+
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--baseline", filepath.Join(temp, "missing.json"))
+	assert.ErrorContains(t, output.Error, "error opening baseline report")
+}
+
+func TestRunCommandRepeat(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	mockConfig := config.TestSuite{
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/users", ExpectedStatus: 200}},
+	}
+	mockConfigFilePath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--repeat", "3")
+	assert.NoError(t, output.Error)
+	assert.Equal(t, int32(3), hits.Load())
+}
+
+func TestRunCommandExportsMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	var pushedPath string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushedPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	mockConfig := config.TestSuite{
+		Name:      "checkout",
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/users", ExpectedStatus: 200}},
+	}
+	temp := t.TempDir()
+	mockConfigFilePath := filepath.Join(temp, "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+	metricsFilePath := filepath.Join(temp, "smokesweep.prom")
+
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--metrics-file", metricsFilePath, "--pushgateway", gateway.URL, "--push-job", "deploy")
+	assert.NoError(t, output.Error)
+	assert.Equal(t, "/metrics/job/deploy/suite/checkout", pushedPath)
+
+	data, err := os.ReadFile(metricsFilePath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `smokesweep_probe_success{endpoint="/users",environment="",suite="checkout",tags=""} 1`)
+}
+
 func TestRunCommandInvalidConfig(t *testing.T) {
 	output := ExecuteTestCommand(GetRunCommand, "-f", "non-existent.yaml")
 	assert.ErrorContains(t, output.Error, "no such file or directory")
@@ -147,3 +289,310 @@ func TestPingCommandUnreachable(t *testing.T) {
 	output := ExecuteTestCommand(GetPingCommand, server.URL, "--timeout", "1s")
 	assert.ErrorContains(t, output.Error, "failed to reach target")
 }
+
+func TestMonitorCommandInvalidInterval(t *testing.T) {
+	mockConfig := config.TestSuite{
+		URL:       "https://example.com",
+		Endpoints: []config.Endpoint{{Path: "/users", ExpectedStatus: 200}},
+	}
+	mockConfigFilePath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+
+	output := ExecuteTestCommand(GetMonitorCommand, "-f", mockConfigFilePath, "--interval", "0s")
+	assert.ErrorContains(t, output.Error, "interval must be positive")
+}
+
+func TestMonitorCommandInvalidConfig(t *testing.T) {
+	output := ExecuteTestCommand(GetMonitorCommand, "-f", "non-existent.yaml")
+	assert.ErrorContains(t, output.Error, "no such file or directory")
+}
+
+func TestMockCommandInvalidConfig(t *testing.T) {
+	output := ExecuteTestCommand(GetMockCommand, "-f", "non-existent.yaml")
+	assert.ErrorContains(t, output.Error, "no such file or directory")
+}
+
+func TestMockCommandInvalidAddress(t *testing.T) {
+	mockConfig := config.TestSuite{
+		URL:       "https://example.com",
+		Endpoints: []config.Endpoint{{Path: "/users", ExpectedStatus: 200}},
+	}
+	mockConfigFilePath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+
+	output := ExecuteTestCommand(GetMockCommand, "-f", mockConfigFilePath, "--addr", "not-an-address")
+	assert.ErrorContains(t, output.Error, "error listening on not-an-address")
+}
+
+func TestDiffCommand(t *testing.T) {
+	newServer := func(status int, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+		}))
+	}
+	base := newServer(http.StatusOK, `{"id": 1, "request_id": "a"}`)
+	defer base.Close()
+	same := newServer(http.StatusOK, `{"id": 2, "request_id": "b"}`)
+	defer same.Close()
+	changed := newServer(http.StatusOK, `{"id": "2"}`)
+	defer changed.Close()
+
+	mockConfig := config.TestSuite{
+		URL:       base.URL,
+		Endpoints: []config.Endpoint{{Path: "/users", ExpectedStatus: 200}},
+	}
+	mockConfigFilePath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+
+	output := ExecuteTestCommand(GetDiffCommand, "-f", mockConfigFilePath, "--base-url", base.URL, "--compare-url", same.URL)
+	assert.NoError(t, output.Error)
+
+	output = ExecuteTestCommand(GetDiffCommand, "-f", mockConfigFilePath, "--base-url", base.URL, "--compare-url", changed.URL)
+	assert.ErrorContains(t, output.Error, "1 endpoint(s) diverged")
+
+	output = ExecuteTestCommand(GetDiffCommand, "-f", mockConfigFilePath, "--base-url", base.URL, "--compare-url", changed.URL, "--ignore-field", "id", "--ignore-field", "request_id")
+	assert.NoError(t, output.Error)
+}
+
+func TestDiffCommandMissingURL(t *testing.T) {
+	output := ExecuteTestCommand(GetDiffCommand, "--base-url", "https://example.com")
+	assert.ErrorContains(t, output.Error, "both --base-url and --compare-url are required")
+}
+
+func TestHistoryAndTrendCommands(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	mockConfig := config.TestSuite{
+		Name:      "history-test",
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/search", ExpectedStatus: 200}},
+	}
+	temp := t.TempDir()
+	mockConfigFilePath := filepath.Join(temp, "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+	historyDir := filepath.Join(temp, "history")
+
+	output := ExecuteTestCommand(GetHistoryCommand, "--history-dir", historyDir)
+	assert.NoError(t, output.Error, "an empty history should not be an error")
+
+	for i := 0; i < 2; i++ {
+		output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir)
+		assert.NoError(t, output.Error)
+	}
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir, "--no-history")
+	assert.NoError(t, output.Error)
+
+	store, err := history.Open(historyDir)
+	assert.NoError(t, err)
+	entries, err := store.List(history.Query{Suite: "history-test"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	output = ExecuteTestCommand(GetHistoryCommand, "--history-dir", historyDir, "--suite", "history-test")
+	assert.NoError(t, output.Error)
+
+	output = ExecuteTestCommand(GetTrendCommand, "/search", "--history-dir", historyDir, "--bucket", "1h")
+	assert.NoError(t, output.Error)
+
+	output = ExecuteTestCommand(GetTrendCommand, "/search", "--history-dir", historyDir, "--bucket", "0s")
+	assert.ErrorContains(t, output.Error, "bucket width must be positive")
+}
+
+func TestRunCommandRecordsEnforcedOutcomes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	maxLatency := 1
+	mockConfig := config.TestSuite{
+		Name:      "enforced-test",
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/slow", ExpectedStatus: 200, MaxLatency: &maxLatency}},
+	}
+	temp := t.TempDir()
+	mockConfigFilePath := filepath.Join(temp, "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+	historyDir := filepath.Join(temp, "history")
+
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir, "--strict-latency")
+	assert.ErrorContains(t, output.Error, "1 endpoint(s) exceeded their max latency")
+
+	store, err := history.Open(historyDir)
+	assert.NoError(t, err)
+	entries, err := store.List(history.Query{Suite: "enforced-test"})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.True(t, entries[0].Failed(), "the history should record the latency breach as a failure")
+	}
+}
+
+func TestRunCommandQuarantineFlaky(t *testing.T) {
+	var status atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+	mockConfig := config.TestSuite{
+		Name:      "flaky-test",
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/flaky", ExpectedStatus: 200}},
+	}
+	temp := t.TempDir()
+	mockConfigFilePath := filepath.Join(temp, "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+	historyDir := filepath.Join(temp, "history")
+
+	record := func(codes ...int32) {
+		for _, code := range codes {
+			status.Store(code)
+			output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir)
+			assert.Equal(t, code == http.StatusOK, output.Error == nil, output.Error)
+		}
+	}
+
+	record(http.StatusOK, http.StatusOK, http.StatusInternalServerError)
+	status.Store(http.StatusInternalServerError)
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir, "--no-history", "--quarantine-flaky")
+	assert.EqualError(t, output.Error, "1 endpoint(s) failed", "a short history should not be scored as flaky")
+
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir, "--no-history", "--quarantine-flaky", "--flaky-threshold", "0")
+	assert.EqualError(t, output.Error, "1 endpoint(s) failed", "a zero threshold should not quarantine consistent endpoints")
+
+	record(http.StatusOK, http.StatusInternalServerError, http.StatusOK)
+	status.Store(http.StatusInternalServerError)
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir, "--no-history", "--fail-fast")
+	assert.ErrorContains(t, output.Error, "expected HTTP 200 but got 500")
+
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir, "--no-history")
+	assert.EqualError(t, output.Error, "1 endpoint(s) failed")
+
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir, "--no-history", "--fail-fast", "--quarantine-flaky")
+	assert.NoError(t, output.Error, "a quarantined flaky endpoint should not fail the run")
+
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir, "--no-history", "--quarantine-flaky")
+	assert.NoError(t, output.Error, "a quarantined flaky endpoint should not fail the run")
+}
+
+func TestRunCommandHistoryIsScopedToURL(t *testing.T) {
+	var status atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+	temp := t.TempDir()
+	historyDir := filepath.Join(temp, "history")
+
+	store, err := history.Open(historyDir)
+	assert.NoError(t, err)
+	for i := 0; i < 6; i++ {
+		assert.NoError(t, store.Append(history.Entry{
+			URL: "https://other.example.com",
+			Report: runner.TestReport{
+				Timestamp: time.Now(),
+				Results:   []runner.TestResult{{Path: "/health", Passed: i%2 == 0}},
+			},
+		}))
+	}
+
+	mockConfig := config.TestSuite{
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/health", ExpectedStatus: 200}},
+	}
+	mockConfigFilePath := filepath.Join(temp, "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+
+	status.Store(http.StatusInternalServerError)
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir, "--quarantine-flaky")
+	assert.EqualError(t, output.Error, "1 endpoint(s) failed", "runs of unrelated unnamed suites should not make the endpoint flaky")
+}
+
+func TestRunCommandNotifies(t *testing.T) {
+	var notifications atomic.Int32
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifications.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+	var status atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+	mockConfig := config.TestSuite{
+		Name:      "notify-test",
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/users", ExpectedStatus: 200}},
+		Notify: []config.NotifyTarget{
+			{URL: webhook.URL, Template: "slack"},
+			{URL: webhook.URL, When: "on-recovery"},
+		},
+	}
+	temp := t.TempDir()
+	mockConfigFilePath := filepath.Join(temp, "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+	historyDir := filepath.Join(temp, "history")
+
+	for _, tc := range []struct {
+		status   int32
+		expected int32
+	}{
+		{status: http.StatusOK, expected: 0},
+		{status: http.StatusInternalServerError, expected: 1},
+		{status: http.StatusOK, expected: 2},
+	} {
+		status.Store(tc.status)
+		output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", historyDir)
+		assert.Equal(t, tc.status == http.StatusOK, output.Error == nil, output.Error)
+		assert.Equal(t, tc.expected, notifications.Load())
+	}
+}
+
+func TestRunCommandNotifiesEnforcedFailures(t *testing.T) {
+	var notifications atomic.Int32
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifications.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	maxLatency := 1
+	mockConfig := config.TestSuite{
+		Name:      "enforced-notify-test",
+		URL:       server.URL,
+		Endpoints: []config.Endpoint{{Path: "/slow", ExpectedStatus: 200, MaxLatency: &maxLatency}},
+		Notify:    []config.NotifyTarget{{URL: webhook.URL}},
+	}
+	temp := t.TempDir()
+	mockConfigFilePath := filepath.Join(temp, "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", filepath.Join(temp, "history"))
+	assert.NoError(t, output.Error)
+	assert.Zero(t, notifications.Load())
+
+	output = ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath, "--history-dir", filepath.Join(temp, "history"), "--strict-latency")
+	assert.Error(t, output.Error)
+	assert.Equal(t, int32(1), notifications.Load(), "a run failing through --strict-latency should notify")
+}
+
+func TestRunCommandInvalidNotifyTarget(t *testing.T) {
+	mockConfig := config.TestSuite{
+		URL:       "https://example.com",
+		Endpoints: []config.Endpoint{{Path: "/users", ExpectedStatus: 200}},
+		Notify:    []config.NotifyTarget{{URL: "https://hooks.example.com", When: "sometimes"}},
+	}
+	mockConfigFilePath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, mockConfig.Write(mockConfigFilePath))
+
+	output := ExecuteTestCommand(GetRunCommand, "-f", mockConfigFilePath)
+	assert.ErrorContains(t, output.Error, "unknown when 'sometimes'")
+}
@@ -0,0 +1,214 @@
+// Package diff compares the responses of the same test suite run against
+// two environments.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+// Kind is the aspect in which two responses differ.
+type Kind string
+
+const (
+	KindReachability Kind = "reachability"
+	KindStatus       Kind = "status"
+	KindContentType  Kind = "content-type"
+	KindBody         Kind = "body"
+	KindLatency      Kind = "latency"
+)
+
+// Difference is a single divergence between the two environments.
+type Difference struct {
+	// Path is the configured path of the endpoint.
+	Path string `json:"path"`
+
+	// Kind is the aspect in which the responses differ.
+	Kind Kind `json:"kind"`
+
+	// Detail describes the difference.
+	Detail string `json:"detail"`
+}
+
+// Options controls which differences are reported.
+type Options struct {
+	// IgnoreFields are JSON fields excluded from the body comparison, given
+	// either as a key name matched at any depth or as a dotted path.
+	IgnoreFields []string
+
+	// LatencyDeltaPercent is the relative latency change above which a
+	// latency difference is reported.
+	LatencyDeltaPercent float64
+
+	// MinLatencyDelta is the absolute latency change below which latency
+	// differences are never reported.
+	MinLatencyDelta time.Duration
+}
+
+// Compare returns the differences between the results of the base and
+// compare runs, matched by endpoint path.
+func Compare(base runner.TestReport, compare runner.TestReport, opts Options) []Difference {
+	byPath := make(map[string]runner.TestResult, len(compare.Results))
+	for _, result := range compare.Results {
+		byPath[result.Path] = result
+	}
+	compareUnreachable := make(map[string]bool, len(compare.Unreachable))
+	for _, result := range compare.Unreachable {
+		compareUnreachable[result.Path] = true
+	}
+
+	var differences []Difference
+	for _, result := range base.Unreachable {
+		if _, ok := byPath[result.Path]; ok {
+			differences = append(differences, Difference{Path: result.Path, Kind: KindReachability, Detail: "unreachable in base but reachable in compare"})
+		}
+	}
+	for _, baseResult := range base.Results {
+		compareResult, ok := byPath[baseResult.Path]
+		if !ok {
+			if compareUnreachable[baseResult.Path] {
+				differences = append(differences, Difference{Path: baseResult.Path, Kind: KindReachability, Detail: "reachable in base but unreachable in compare"})
+			}
+			continue
+		}
+		differences = append(differences, compareResults(baseResult, compareResult, opts)...)
+	}
+	return differences
+}
+
+// compareResults returns the differences between two results of an endpoint.
+func compareResults(base runner.TestResult, compare runner.TestResult, opts Options) []Difference {
+	var differences []Difference
+	add := func(kind Kind, format string, args ...any) {
+		differences = append(differences, Difference{Path: base.Path, Kind: kind, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	if base.HttpStatus != compare.HttpStatus {
+		add(KindStatus, "HTTP %d vs %d", base.HttpStatus, compare.HttpStatus)
+	}
+	baseType, compareType := mediaType(base.ContentType), mediaType(compare.ContentType)
+	if baseType != compareType {
+		add(KindContentType, "'%s' vs '%s'", base.ContentType, compare.ContentType)
+	}
+	if baseType == "application/json" && compareType == "application/json" {
+		for _, detail := range compareJSON(base.Body, compare.Body, opts.IgnoreFields) {
+			add(KindBody, "%s", detail)
+		}
+	}
+	if latencyDiverges(base.Duration, compare.Duration, opts) {
+		add(KindLatency, "%vms vs %vms", base.Duration.Milliseconds(), compare.Duration.Milliseconds())
+	}
+	return differences
+}
+
+// latencyDiverges reports whether the latency change exceeds both thresholds.
+func latencyDiverges(base time.Duration, compare time.Duration, opts Options) bool {
+	delta := compare - base
+	if delta < 0 {
+		delta = -delta
+	}
+	if delta < opts.MinLatencyDelta || base <= 0 {
+		return false
+	}
+	return float64(delta)/float64(base)*100 > opts.LatencyDeltaPercent
+}
+
+func mediaType(contentType string) string {
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return parsed
+}
+
+// compareJSON describes the structural differences between two JSON bodies.
+func compareJSON(base []byte, compare []byte, ignore []string) []string {
+	var baseValue, compareValue any
+	baseErr := json.Unmarshal(base, &baseValue)
+	compareErr := json.Unmarshal(compare, &compareValue)
+	switch {
+	case baseErr != nil && compareErr != nil:
+		return nil
+	case baseErr != nil:
+		return []string{"base body is not valid JSON"}
+	case compareErr != nil:
+		return []string{"compare body is not valid JSON"}
+	}
+
+	baseShape := make(map[string]string)
+	compareShape := make(map[string]string)
+	flattenShape(baseValue, "", ignore, baseShape)
+	flattenShape(compareValue, "", ignore, compareShape)
+
+	var details []string
+	for _, field := range sortedKeys(baseShape) {
+		compareKind, ok := compareShape[field]
+		switch {
+		case !ok:
+			details = append(details, fmt.Sprintf("field '%s' missing in compare", field))
+		case compareKind != baseShape[field]:
+			details = append(details, fmt.Sprintf("field '%s' is %s vs %s", field, baseShape[field], compareKind))
+		}
+	}
+	for _, field := range sortedKeys(compareShape) {
+		if _, ok := baseShape[field]; !ok {
+			details = append(details, fmt.Sprintf("field '%s' missing in base", field))
+		}
+	}
+	return details
+}
+
+// flattenShape records the JSON type of every field by its dotted path.
+// Arrays are represented by the shape of their first element.
+func flattenShape(value any, path string, ignore []string, shape map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		shape[rootPath(path)] = "object"
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if slices.Contains(ignore, key) || slices.Contains(ignore, childPath) {
+				continue
+			}
+			flattenShape(child, childPath, ignore, shape)
+		}
+	case []any:
+		shape[rootPath(path)] = "array"
+		if len(v) > 0 {
+			flattenShape(v[0], path+"[]", ignore, shape)
+		}
+	case string:
+		shape[rootPath(path)] = "string"
+	case float64:
+		shape[rootPath(path)] = "number"
+	case bool:
+		shape[rootPath(path)] = "boolean"
+	case nil:
+		shape[rootPath(path)] = "null"
+	}
+}
+
+func rootPath(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+func TestCompare(t *testing.T) {
+	opts := Options{
+		IgnoreFields:        []string{"request_id", "meta.generated_at"},
+		LatencyDeltaPercent: 50,
+		MinLatencyDelta:     100 * time.Millisecond,
+	}
+	jsonResult := func(path string, status int, body string, duration time.Duration) runner.TestResult {
+		return runner.TestResult{
+			Path:        path,
+			HttpStatus:  status,
+			ContentType: "application/json; charset=utf-8",
+			Body:        []byte(body),
+			Duration:    duration,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		base     runner.TestReport
+		compare  runner.TestReport
+		expected []Difference
+	}{
+		{
+			name:    "Identical responses",
+			base:    runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `[{"id": 1}]`, 100*time.Millisecond)}},
+			compare: runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `[{"id": 2}]`, 120*time.Millisecond)}},
+		},
+		{
+			name:    "Status differs",
+			base:    runner.TestReport{Results: []runner.TestResult{{Path: "/health", HttpStatus: 200}}},
+			compare: runner.TestReport{Results: []runner.TestResult{{Path: "/health", HttpStatus: 503}}},
+			expected: []Difference{
+				{Path: "/health", Kind: KindStatus, Detail: "HTTP 200 vs 503"},
+			},
+		},
+		{
+			name: "Content type differs",
+			base: runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `{}`, 0)}},
+			compare: runner.TestReport{Results: []runner.TestResult{
+				{Path: "/users", HttpStatus: 200, ContentType: "text/html"},
+			}},
+			expected: []Difference{
+				{Path: "/users", Kind: KindContentType, Detail: "'application/json; charset=utf-8' vs 'text/html'"},
+			},
+		},
+		{
+			name:    "Body structure differs",
+			base:    runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `{"id": 1, "name": "Ada", "tags": []}`, 0)}},
+			compare: runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `{"id": "1", "email": "a@b.c", "tags": []}`, 0)}},
+			expected: []Difference{
+				{Path: "/users", Kind: KindBody, Detail: "field 'id' is number vs string"},
+				{Path: "/users", Kind: KindBody, Detail: "field 'name' missing in compare"},
+				{Path: "/users", Kind: KindBody, Detail: "field 'email' missing in base"},
+			},
+		},
+		{
+			name:    "Nested array elements are compared",
+			base:    runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `{"items": [{"id": 1}]}`, 0)}},
+			compare: runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `{"items": [{"id": null}]}`, 0)}},
+			expected: []Difference{
+				{Path: "/users", Kind: KindBody, Detail: "field 'items[].id' is number vs null"},
+			},
+		},
+		{
+			name:    "Ignored fields are skipped",
+			base:    runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `{"request_id": "a", "meta": {"generated_at": 1}}`, 0)}},
+			compare: runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `{"meta": {"generated_at": "now"}}`, 0)}},
+		},
+		{
+			name:    "Invalid JSON body",
+			base:    runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `{}`, 0)}},
+			compare: runner.TestReport{Results: []runner.TestResult{jsonResult("/users", 200, `<html>`, 0)}},
+			expected: []Difference{
+				{Path: "/users", Kind: KindBody, Detail: "compare body is not valid JSON"},
+			},
+		},
+		{
+			name:    "Large latency delta",
+			base:    runner.TestReport{Results: []runner.TestResult{{Path: "/slow", HttpStatus: 200, Duration: 200 * time.Millisecond}}},
+			compare: runner.TestReport{Results: []runner.TestResult{{Path: "/slow", HttpStatus: 200, Duration: 900 * time.Millisecond}}},
+			expected: []Difference{
+				{Path: "/slow", Kind: KindLatency, Detail: "200ms vs 900ms"},
+			},
+		},
+		{
+			name:    "Small absolute latency delta is ignored",
+			base:    runner.TestReport{Results: []runner.TestResult{{Path: "/fast", HttpStatus: 200, Duration: 5 * time.Millisecond}}},
+			compare: runner.TestReport{Results: []runner.TestResult{{Path: "/fast", HttpStatus: 200, Duration: 50 * time.Millisecond}}},
+		},
+		{
+			name:    "Unreachable in compare",
+			base:    runner.TestReport{Results: []runner.TestResult{{Path: "/users", HttpStatus: 200}}},
+			compare: runner.TestReport{Unreachable: []runner.TestResult{{Path: "/users"}}},
+			expected: []Difference{
+				{Path: "/users", Kind: KindReachability, Detail: "reachable in base but unreachable in compare"},
+			},
+		},
+		{
+			name:    "Unreachable in base",
+			base:    runner.TestReport{Unreachable: []runner.TestResult{{Path: "/users"}}},
+			compare: runner.TestReport{Results: []runner.TestResult{{Path: "/users", HttpStatus: 200}}},
+			expected: []Difference{
+				{Path: "/users", Kind: KindReachability, Detail: "unreachable in base but reachable in compare"},
+			},
+		},
+		{
+			name:    "Unreachable in both",
+			base:    runner.TestReport{Unreachable: []runner.TestResult{{Path: "/users"}}},
+			compare: runner.TestReport{Unreachable: []runner.TestResult{{Path: "/users"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Compare(tt.base, tt.compare, opts))
+		})
+	}
+}
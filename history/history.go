@@ -0,0 +1,271 @@
+// Package history persists test reports to a local append-only store so
+// that results can be compared over time.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+// FileName is the name of the history file within the store directory.
+const FileName string = "history.jsonl"
+
+// Entry is a single recorded run.
+type Entry struct {
+	// Suite is the name of the suite that was run.
+	Suite string `json:"suite,omitempty"`
+
+	// Environment is the environment of the suite that was run.
+	Environment string `json:"environment,omitempty"`
+
+	// URL is the base URL the suite was run against.
+	URL string `json:"url"`
+
+	// Report is the report of the run.
+	Report runner.TestReport `json:"report"`
+}
+
+// Passed returns the number of passing endpoints in the run.
+func (e Entry) Passed() int {
+	passed := 0
+	for _, result := range e.Report.Results {
+		if result.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+// Failed reports whether any endpoint that is not quarantined failed in the
+// run.
+func (e Entry) Failed() bool {
+	return !e.Report.Passed()
+}
+
+// Total returns the number of endpoints tested in the run.
+func (e Entry) Total() int {
+	return len(e.Report.Results) + len(e.Report.Unreachable)
+}
+
+// Store is a JSON Lines file holding one entry per run.
+type Store struct {
+	path string
+}
+
+// DefaultDir returns the default store directory, following the XDG base
+// directory convention.
+func DefaultDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "smokesweep"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "smokesweep"), nil
+}
+
+// Open returns the store in the given directory, creating the directory if
+// needed. An empty directory selects DefaultDir.
+func Open(dir string) (*Store, error) {
+	if dir == "" {
+		defaultDir, err := DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = defaultDir
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating history directory: %w", err)
+	}
+	return &Store{path: filepath.Join(dir, FileName)}, nil
+}
+
+// Path returns the path of the history file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append records a run at the end of the store.
+func (s *Store) Append(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding history entry: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening history file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing history entry: %w", err)
+	}
+	return nil
+}
+
+// Query selects entries from the store. Zero values match everything.
+type Query struct {
+	// Suite restricts the entries to runs of the suite with this name.
+	Suite string
+
+	// URL restricts the entries to runs against this base URL.
+	URL string
+
+	// Since excludes runs that started before this time.
+	Since time.Time
+}
+
+// List returns the entries matching the query, oldest first.
+func (s *Store) List(query Query) ([]Entry, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	decoder := json.NewDecoder(file)
+	for {
+		var entry Entry
+		err := decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading history file: %w", err)
+		}
+		if query.Suite != "" && entry.Suite != query.Suite {
+			continue
+		}
+		if query.URL != "" && entry.URL != query.URL {
+			continue
+		}
+		if entry.Report.Timestamp.Before(query.Since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Bucket aggregates the results of an endpoint over a period of time.
+type Bucket struct {
+	// Start is the beginning of the period.
+	Start time.Time
+
+	// Stats is the latency distribution of the reachable results, with
+	// Samples counting every run of the endpoint including unreachable ones.
+	Stats runner.LatencyStats
+}
+
+// Trend aggregates the results of the endpoint with the given path into
+// consecutive buckets of the given width, starting at since. Buckets without
+// any run of the endpoint are omitted.
+func Trend(entries []Entry, path string, since time.Time, width time.Duration) ([]Bucket, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("bucket width must be positive, got %s", width)
+	}
+	type accumulator struct {
+		durations   []time.Duration
+		successes   int
+		unreachable int
+	}
+	var order []int
+	accumulators := make(map[int]*accumulator)
+	for _, entry := range entries {
+		if entry.Report.Timestamp.Before(since) {
+			continue
+		}
+		index := int(entry.Report.Timestamp.Sub(since) / width)
+		acc, ok := accumulators[index]
+		if !ok {
+			acc = &accumulator{}
+		}
+		matched := false
+		for _, result := range entry.Report.Results {
+			if !samePath(result.Path, path) {
+				continue
+			}
+			matched = true
+			acc.durations = append(acc.durations, result.Duration)
+			if result.Passed {
+				acc.successes++
+			}
+		}
+		for _, result := range entry.Report.Unreachable {
+			if samePath(result.Path, path) {
+				matched = true
+				acc.unreachable++
+			}
+		}
+		if matched && !ok {
+			accumulators[index] = acc
+			order = append(order, index)
+		}
+	}
+
+	sort.Ints(order)
+	buckets := make([]Bucket, 0, len(order))
+	for _, index := range order {
+		acc := accumulators[index]
+		stats := runner.NewLatencyStats(acc.durations)
+		stats.Samples += acc.unreachable
+		stats.Successes = acc.successes
+		buckets = append(buckets, Bucket{
+			Start: since.Add(time.Duration(index) * width),
+			Stats: stats,
+		})
+	}
+	return buckets, nil
+}
+
+// Summarize aggregates every result of the endpoint with the given path since
+// the given time. It returns false if the endpoint was not run in that window.
+func Summarize(entries []Entry, path string, since time.Time) (runner.LatencyStats, bool) {
+	buckets, _ := Trend(entries, path, since, time.Duration(math.MaxInt64))
+	if len(buckets) == 0 {
+		return runner.LatencyStats{}, false
+	}
+	return buckets[0].Stats, true
+}
+
+// Outcomes returns whether the endpoint with the given path passed in each of
+// its last n runs, oldest first. Unreachable runs count as failures.
+func Outcomes(entries []Entry, path string, n int) []bool {
+	var outcomes []bool
+	for _, entry := range entries {
+		for _, result := range entry.Report.Results {
+			if samePath(result.Path, path) {
+				outcomes = append(outcomes, result.Passed)
+			}
+		}
+		for _, result := range entry.Report.Unreachable {
+			if samePath(result.Path, path) {
+				outcomes = append(outcomes, false)
+			}
+		}
+	}
+	if len(outcomes) > n {
+		outcomes = outcomes[len(outcomes)-n:]
+	}
+	return outcomes
+}
+
+// samePath reports whether two endpoint paths are equal, ignoring a leading
+// slash.
+func samePath(a string, b string) bool {
+	return strings.TrimLeft(a, "/") == strings.TrimLeft(b, "/")
+}
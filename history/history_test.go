@@ -0,0 +1,147 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+func newEntry(suite string, timestamp time.Time, results ...runner.TestResult) Entry {
+	return Entry{
+		Suite:  suite,
+		URL:    "https://example.com",
+		Report: runner.TestReport{Timestamp: timestamp, Results: results},
+	}
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/data")
+	dir, err := DefaultDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/data", "smokesweep"), dir)
+
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("HOME", "/home/tester")
+	dir, err = DefaultDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/home/tester", ".local", "share", "smokesweep"), dir)
+}
+
+func TestStore_AppendAndList(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	store, err := Open(dir)
+	require.NoError(t, err)
+
+	entries, err := store.List(Query{})
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a missing history file should be treated as empty")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, store.Append(newEntry("checkout", now.Add(-48*time.Hour), runner.TestResult{Path: "/cart", Passed: true})))
+	require.NoError(t, store.Append(newEntry("billing", now.Add(-time.Hour), runner.TestResult{Path: "/invoices"})))
+	require.NoError(t, store.Append(newEntry("checkout", now, runner.TestResult{Path: "/cart", Passed: true})))
+
+	entries, err = store.List(Query{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "checkout", entries[0].Suite)
+	assert.Equal(t, 1, entries[0].Passed())
+	assert.Equal(t, 1, entries[0].Total())
+
+	entries, err = store.List(Query{Suite: "checkout", Since: now.Add(-24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, entries[0].Report.Timestamp.Equal(now))
+
+	staging := newEntry("", now, runner.TestResult{Path: "/cart"})
+	staging.URL = "https://staging.example.com"
+	require.NoError(t, store.Append(staging))
+	entries, err = store.List(Query{URL: "https://staging.example.com"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Empty(t, entries[0].Suite)
+}
+
+func TestStore_ListCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte("{not json\n"), 0o644))
+	store, err := Open(dir)
+	require.NoError(t, err)
+
+	_, err = store.List(Query{})
+	assert.ErrorContains(t, err, "error reading history file")
+}
+
+func TestTrend(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	result := func(path string, ms int, passed bool) runner.TestResult {
+		return runner.TestResult{Path: path, Duration: time.Duration(ms) * time.Millisecond, Passed: passed}
+	}
+	entries := []Entry{
+		newEntry("api", start.Add(-time.Hour), result("/search", 999, true)),
+		newEntry("api", start.Add(time.Hour), result("/search", 100, true), result("/users", 10, true)),
+		newEntry("api", start.Add(2*time.Hour), result("/search", 200, false)),
+		newEntry("api", start.Add(50*time.Hour), result("/search", 300, true)),
+		{Suite: "api", Report: runner.TestReport{
+			Timestamp:   start.Add(51 * time.Hour),
+			Unreachable: []runner.TestResult{{Path: "/search"}},
+		}},
+		newEntry("api", start.Add(30*time.Hour), result("/users", 10, true)),
+	}
+
+	buckets, err := Trend(entries, "search", start, 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, buckets, 2, "periods without runs of the endpoint should be omitted")
+
+	assert.Equal(t, start, buckets[0].Start)
+	assert.Equal(t, 2, buckets[0].Stats.Samples)
+	assert.Equal(t, 1, buckets[0].Stats.Successes)
+	assert.Equal(t, 100*time.Millisecond, buckets[0].Stats.P50)
+	assert.Equal(t, 200*time.Millisecond, buckets[0].Stats.P90)
+
+	assert.Equal(t, start.Add(48*time.Hour), buckets[1].Start)
+	assert.Equal(t, 2, buckets[1].Stats.Samples)
+	assert.Equal(t, 1, buckets[1].Stats.Successes)
+	assert.Equal(t, 300*time.Millisecond, buckets[1].Stats.Max)
+
+	_, err = Trend(entries, "/search", start, 0)
+	assert.ErrorContains(t, err, "bucket width must be positive")
+}
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		newEntry("api", start.Add(time.Hour), runner.TestResult{Path: "/search", Duration: 100 * time.Millisecond, Passed: true}),
+		newEntry("api", start.Add(100*time.Hour), runner.TestResult{Path: "/search", Duration: 300 * time.Millisecond}),
+	}
+
+	stats, ok := Summarize(entries, "/search", start)
+	require.True(t, ok)
+	assert.Equal(t, 2, stats.Samples)
+	assert.Equal(t, 1, stats.Successes)
+	assert.Equal(t, 200*time.Millisecond, stats.Mean)
+
+	_, ok = Summarize(entries, "/missing", start)
+	assert.False(t, ok)
+}
+
+func TestOutcomes(t *testing.T) {
+	now := time.Now()
+	entries := []Entry{
+		newEntry("api", now, runner.TestResult{Path: "/search", Passed: true}),
+		newEntry("api", now, runner.TestResult{Path: "/users", Passed: true}),
+		{Report: runner.TestReport{Timestamp: now, Unreachable: []runner.TestResult{{Path: "/search"}}}},
+		newEntry("api", now, runner.TestResult{Path: "search", Passed: true}),
+		newEntry("api", now, runner.TestResult{Path: "/search", Passed: false}),
+	}
+
+	assert.Equal(t, []bool{true, false, true, false}, Outcomes(entries, "/search", 10))
+	assert.Equal(t, []bool{true, false}, Outcomes(entries, "/search", 2))
+	assert.Empty(t, Outcomes(entries, "/missing", 10))
+}
@@ -31,6 +31,12 @@ func main() {
 	commandsList := []*cobra.Command{
 		core.GetRunCommand(),
 		core.GetPingCommand(),
+		core.GetMonitorCommand(),
+		core.GetServeCommand(),
+		core.GetMockCommand(),
+		core.GetDiffCommand(),
+		core.GetHistoryCommand(),
+		core.GetTrendCommand(),
 	}
 	command := core.NewCommandRegistry(projectName, projectDescription, version)
 	command.RegisterCommands(commandsList)
@@ -39,5 +45,6 @@ func main() {
 	err := command.Execute()
 	if err != nil {
 		logger.Error(err.Error())
+		os.Exit(1)
 	}
 }
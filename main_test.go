@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cliModeEnv makes the test binary run the CLI with the remaining arguments.
+const cliModeEnv = "SMOKESWEEP_TEST_CLI"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(cliModeEnv) != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runCLI runs the CLI in a subprocess and returns its exit code.
+func runCLI(t *testing.T, args ...string) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), cliModeEnv+"=1")
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	require.NoError(t, err)
+	return 0
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, runCLI(t, "--version"))
+	assert.Equal(t, 1, runCLI(t, "run", "-f", filepath.Join(t.TempDir(), "missing.yaml")), "a failing command should exit non-zero")
+	assert.Equal(t, 1, runCLI(t, "diff", "--base-url", "https://example.com"))
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// DefaultPushJob is the Pushgateway job name used when none is provided.
+const DefaultPushJob string = "smokesweep"
+
+// WriteTextfile atomically writes the collected metrics to a file in the
+// format read by the node_exporter textfile collector.
+func (c *Collector) WriteTextfile(filePath string) error {
+	if err := prometheus.WriteToTextfile(filePath, c.registry); err != nil {
+		return fmt.Errorf("error writing metrics file: %w", err)
+	}
+	return nil
+}
+
+// Push replaces the metrics of the suite and environment under the job on the
+// Pushgateway at the given URL with the collected metrics. The suite and
+// environment form the grouping key so that suites pushing to the same job
+// keep their own metrics.
+func (c *Collector) Push(ctx context.Context, gatewayURL string, job string) error {
+	if job == "" {
+		job = DefaultPushJob
+	}
+	pusher := push.New(gatewayURL, job)
+	grouping := groupingGatherer{gatherer: c.registry, labels: map[string]bool{}}
+	for name, value := range map[string]string{"suite": c.suite.Name, "environment": c.suite.Environment} {
+		if value != "" {
+			pusher = pusher.Grouping(name, value)
+			grouping.labels[name] = true
+		}
+	}
+	if err := pusher.Gatherer(grouping).PushContext(ctx); err != nil {
+		return fmt.Errorf("error pushing metrics to %s: %w", gatewayURL, err)
+	}
+	return nil
+}
+
+// groupingGatherer removes the labels that are part of the grouping key of a
+// push from the gathered metrics, as the Pushgateway rejects metrics carrying
+// them and adds them back itself.
+type groupingGatherer struct {
+	gatherer prometheus.Gatherer
+	labels   map[string]bool
+}
+
+func (g groupingGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			kept := metric.Label[:0]
+			for _, label := range metric.GetLabel() {
+				if !g.labels[label.GetName()] {
+					kept = append(kept, label)
+				}
+			}
+			metric.Label = kept
+		}
+	}
+	return families, nil
+}
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+func newObservedCollector() *Collector {
+	collector := NewCollector(newTestSuite())
+	collector.Observe(runner.TestReport{
+		Timestamp: time.Unix(1700000000, 0),
+		Results: []runner.TestResult{
+			{Path: "/users", Duration: 100 * time.Millisecond, HttpStatus: 200, ExpectedStatus: 200, Passed: true},
+		},
+	})
+	return collector
+}
+
+func TestCollector_WriteTextfile(t *testing.T) {
+	collector := newObservedCollector()
+	filePath := filepath.Join(t.TempDir(), "smokesweep.prom")
+
+	require.NoError(t, collector.WriteTextfile(filePath))
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# TYPE "+ProbeSuccessName+" gauge")
+	assert.Contains(t, string(data), ProbeSuccessName+`{endpoint="users",environment="staging",suite="checkout",tags="api,critical"} 1`)
+	assert.Contains(t, string(data), LastRunName)
+
+	err = collector.WriteTextfile(filepath.Join(t.TempDir(), "missing", "smokesweep.prom"))
+	assert.ErrorContains(t, err, "error writing metrics file")
+}
+
+// groupingKey parses the job and grouping labels from a Pushgateway path.
+func groupingKey(t *testing.T, path string) map[string]string {
+	t.Helper()
+	parts := strings.Split(strings.TrimPrefix(path, "/metrics/"), "/")
+	require.Zero(t, len(parts)%2, "path %s should consist of label pairs", path)
+	key := make(map[string]string, len(parts)/2)
+	for i := 0; i < len(parts); i += 2 {
+		key[parts[i]] = parts[i+1]
+	}
+	return key
+}
+
+func TestCollector_Push(t *testing.T) {
+	tests := []struct {
+		name        string
+		suite       *config.TestSuite
+		job         string
+		status      int
+		expectedKey map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "push with default job",
+			status:      http.StatusOK,
+			expectedKey: map[string]string{"job": "smokesweep", "suite": "checkout", "environment": "staging"},
+		},
+		{
+			name:        "push with custom job",
+			job:         "nightly",
+			status:      http.StatusAccepted,
+			expectedKey: map[string]string{"job": "nightly", "suite": "checkout", "environment": "staging"},
+		},
+		{
+			name:        "unnamed suite",
+			suite:       &config.TestSuite{Environment: "production", Endpoints: []config.Endpoint{{Path: "/users"}}},
+			status:      http.StatusOK,
+			expectedKey: map[string]string{"job": "smokesweep", "environment": "production"},
+		},
+		{
+			name:        "gateway rejects push",
+			status:      http.StatusBadRequest,
+			expectedErr: "error pushing metrics",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method, path, body string
+			gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				method, path, body = r.Method, r.URL.Path, string(data)
+				w.WriteHeader(tt.status)
+			}))
+			defer gateway.Close()
+
+			suite := tt.suite
+			if suite == nil {
+				suite = newTestSuite()
+			}
+			collector := NewCollector(suite)
+			collector.Observe(runner.TestReport{
+				Timestamp: time.Unix(1700000000, 0),
+				Results:   []runner.TestResult{{Path: "/users", HttpStatus: 200, ExpectedStatus: 200, Passed: true}},
+			})
+			err := collector.Push(context.Background(), gateway.URL, tt.job)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.MethodPut, method)
+			assert.Equal(t, tt.expectedKey, groupingKey(t, path))
+			assert.Contains(t, body, ProbeSuccessName)
+			for name, value := range tt.expectedKey {
+				if name != "job" {
+					assert.NotContains(t, body, value, "grouping labels should be left to the gateway")
+				}
+			}
+		})
+	}
+}
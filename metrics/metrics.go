@@ -0,0 +1,133 @@
+// Package metrics exposes smoke test results as Prometheus metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+// Metric names shared by every mode that exports results.
+const (
+	ProbeSuccessName      string = "smokesweep_probe_success"
+	ProbeDurationName     string = "smokesweep_probe_duration_seconds"
+	HTTPResponsesName     string = "smokesweep_http_responses_total"
+	CertificateExpiryName string = "smokesweep_certificate_expiry_timestamp_seconds"
+	LastRunName           string = "smokesweep_last_run_timestamp_seconds"
+)
+
+var (
+	suiteLabels    = []string{"suite", "environment"}
+	endpointLabels = []string{"suite", "environment", "endpoint", "tags"}
+	responseLabels = []string{"suite", "environment", "endpoint", "tags", "code"}
+)
+
+// Collector records test reports into a dedicated Prometheus registry.
+type Collector struct {
+	suite             *config.TestSuite
+	endpoints         map[string]config.Endpoint
+	registry          *prometheus.Registry
+	probeSuccess      *prometheus.GaugeVec
+	probeDuration     *prometheus.HistogramVec
+	httpResponses     *prometheus.CounterVec
+	certificateExpiry *prometheus.GaugeVec
+	lastRun           *prometheus.GaugeVec
+}
+
+// NewCollector creates a collector for the results of the given suite.
+func NewCollector(suite *config.TestSuite) *Collector {
+	c := &Collector{
+		suite:     suite,
+		endpoints: make(map[string]config.Endpoint, len(suite.Endpoints)),
+		registry:  prometheus.NewRegistry(),
+		probeSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: ProbeSuccessName,
+			Help: "Whether the most recent check of the endpoint passed (1) or failed (0).",
+		}, endpointLabels),
+		probeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    ProbeDurationName,
+			Help:    "Duration of endpoint checks in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, endpointLabels),
+		httpResponses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: HTTPResponsesName,
+			Help: "Number of HTTP responses received per status code.",
+		}, responseLabels),
+		certificateExpiry: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: CertificateExpiryName,
+			Help: "Expiry time of the endpoint TLS certificate as a Unix timestamp.",
+		}, endpointLabels),
+		lastRun: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: LastRunName,
+			Help: "Time of the most recent run as a Unix timestamp.",
+		}, suiteLabels),
+	}
+	for _, endpoint := range suite.Endpoints {
+		c.endpoints[endpoint.Path] = endpoint
+	}
+	c.registry.MustRegister(c.probeSuccess, c.probeDuration, c.httpResponses, c.certificateExpiry, c.lastRun)
+	return c
+}
+
+// Registry returns the registry holding the collected metrics.
+func (c *Collector) Registry() *prometheus.Registry {
+	return c.registry
+}
+
+// Handler returns an HTTP handler serving the metrics in exposition format.
+func (c *Collector) Handler() http.Handler {
+	return promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{})
+}
+
+// Observe records the results of a test report.
+func (c *Collector) Observe(report runner.TestReport) {
+	for _, result := range report.Results {
+		labels := c.endpointLabels(result.Path)
+		c.probeSuccess.With(labels).Set(boolToFloat(result.Passed))
+		c.probeDuration.With(labels).Observe(result.Duration.Seconds())
+		if result.HttpStatus != 0 {
+			codeLabels := prometheus.Labels{"code": strconv.Itoa(result.HttpStatus)}
+			for k, v := range labels {
+				codeLabels[k] = v
+			}
+			c.httpResponses.With(codeLabels).Inc()
+		}
+		if result.Certificate != nil {
+			c.certificateExpiry.With(labels).Set(float64(result.Certificate.NotAfter.Unix()))
+		}
+	}
+	for _, result := range report.Unreachable {
+		c.probeSuccess.With(c.endpointLabels(result.Path)).Set(0)
+	}
+	c.lastRun.With(prometheus.Labels{
+		"suite":       c.suite.Name,
+		"environment": c.suite.Environment,
+	}).Set(float64(report.Timestamp.Unix()))
+}
+
+// endpointLabels returns the labels identifying the endpoint with the path.
+func (c *Collector) endpointLabels(path string) prometheus.Labels {
+	endpoint, ok := c.endpoints[path]
+	if !ok {
+		endpoint = config.Endpoint{Path: path}
+	}
+	return prometheus.Labels{
+		"suite":       c.suite.Name,
+		"environment": c.suite.Environment,
+		"endpoint":    endpoint.DisplayName(),
+		"tags":        strings.Join(endpoint.Tags, ","),
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+func newTestSuite() *config.TestSuite {
+	return &config.TestSuite{
+		Name:        "checkout",
+		Environment: "staging",
+		URL:         "https://example.com",
+		Endpoints: []config.Endpoint{
+			{Name: "users", Path: "/users", Tags: []string{"api", "critical"}},
+			{Path: "/health"},
+			{Path: "/down"},
+		},
+	}
+}
+
+func scrape(t *testing.T, collector *Collector) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	collector.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestCollector_Observe(t *testing.T) {
+	collector := NewCollector(newTestSuite())
+	timestamp := time.Unix(1700000000, 0)
+	notAfter := time.Unix(1800000000, 0)
+	report := runner.TestReport{
+		Timestamp: timestamp,
+		Results: []runner.TestResult{
+			{
+				Path:           "/users",
+				Duration:       150 * time.Millisecond,
+				HttpStatus:     200,
+				ExpectedStatus: 200,
+				Passed:         true,
+				Certificate:    &runner.CertificateInfo{NotAfter: notAfter},
+			},
+			{Path: "/health", Duration: 20 * time.Millisecond, HttpStatus: 503, ExpectedStatus: 200},
+		},
+		Unreachable: []runner.TestResult{
+			{Path: "/down", Message: "connection refused"},
+		},
+	}
+
+	collector.Observe(report)
+	collector.Observe(report)
+	output := scrape(t, collector)
+
+	usersLabels := `endpoint="users",environment="staging",suite="checkout",tags="api,critical"`
+	healthLabels := `endpoint="/health",environment="staging",suite="checkout",tags=""`
+	assert.Contains(t, output, ProbeSuccessName+`{`+usersLabels+`} 1`)
+	assert.Contains(t, output, ProbeSuccessName+`{`+healthLabels+`} 0`)
+	assert.Contains(t, output, ProbeSuccessName+`{endpoint="/down",environment="staging",suite="checkout",tags=""} 0`)
+	assert.Contains(t, output, ProbeDurationName+`_count{`+usersLabels+`} 2`)
+	assert.Contains(t, output, ProbeDurationName+`_bucket{`+usersLabels+`,le="0.25"} 2`)
+	assert.Contains(t, output, HTTPResponsesName+`{code="200",`+usersLabels+`} 2`)
+	assert.Contains(t, output, HTTPResponsesName+`{code="503",`+healthLabels+`} 2`)
+	assert.Contains(t, output, CertificateExpiryName+`{`+usersLabels+`} 1.8e+09`)
+	assert.NotContains(t, output, CertificateExpiryName+`{`+healthLabels)
+	assert.Contains(t, output, LastRunName+`{environment="staging",suite="checkout"} 1.7e+09`)
+}
+
+func TestCollector_UnknownPath(t *testing.T) {
+	collector := NewCollector(newTestSuite())
+	collector.Observe(runner.TestReport{
+		Timestamp: time.Now(),
+		Results:   []runner.TestResult{{Path: "/unknown", Passed: true}},
+	})
+
+	output := scrape(t, collector)
+	assert.Contains(t, output, ProbeSuccessName+`{endpoint="/unknown",environment="staging",suite="checkout",tags=""} 1`)
+}
@@ -0,0 +1,107 @@
+// Package mock provides an HTTP handler that emulates the target application
+// of a test suite using the expectations in its configuration.
+package mock
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// route is a configured endpoint matched against incoming requests.
+type route struct {
+	path     string
+	query    url.Values
+	endpoint config.Endpoint
+}
+
+// Handler answers each configured endpoint with its expected status and
+// optional canned response. Unknown paths receive a 404.
+type Handler struct {
+	routes []route
+}
+
+// NewHandler creates a mock handler for the HTTP endpoints of the suite.
+func NewHandler(suite *config.TestSuite) (*Handler, error) {
+	h := &Handler{}
+	for _, endpoint := range suite.Endpoints {
+		if endpoint.CheckType() != config.TypeHTTP {
+			continue
+		}
+		parsed, err := url.Parse(endpoint.Path)
+		if err != nil {
+			return nil, err
+		}
+		path := parsed.Path
+		if path == "" {
+			path = "/"
+		} else if path[0] != '/' {
+			path = "/" + path
+		}
+		h.routes = append(h.routes, route{path: path, query: parsed.Query(), endpoint: endpoint})
+	}
+	return h, nil
+}
+
+// ServeHTTP responds with the canned response of the matching endpoint.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint, ok := h.match(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	status := endpoint.ExpectedStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+	var body string
+	if endpoint.Mock != nil {
+		if endpoint.Mock.Latency != nil {
+			select {
+			case <-time.After(time.Duration(*endpoint.Mock.Latency) * time.Millisecond):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		for name, value := range endpoint.Mock.Headers {
+			w.Header().Set(name, value)
+		}
+		body = endpoint.Mock.Body
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
+
+// match returns the endpoint whose path and query parameters match the
+// request, preferring the endpoint with the most specific query.
+func (h *Handler) match(r *http.Request) (config.Endpoint, bool) {
+	best := -1
+	bestParams := -1
+	for i, candidate := range h.routes {
+		if candidate.path != r.URL.Path || !queryMatches(candidate.query, r.URL.Query()) {
+			continue
+		}
+		if len(candidate.query) > bestParams {
+			best, bestParams = i, len(candidate.query)
+		}
+	}
+	if best < 0 {
+		return config.Endpoint{}, false
+	}
+	return h.routes[best].endpoint, true
+}
+
+// queryMatches reports whether every expected parameter is present in the
+// actual query with the same values.
+func queryMatches(expected url.Values, actual url.Values) bool {
+	for key, values := range expected {
+		if !slices.Equal(actual[key], values) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	suite := &config.TestSuite{
+		Endpoints: []config.Endpoint{
+			{Path: "/", ExpectedStatus: 200},
+			{Path: "users", ExpectedStatus: 200, Mock: &config.MockResponse{
+				Body:    `[{"id": 1}]`,
+				Headers: map[string]string{"Content-Type": "application/json"},
+			}},
+			{Path: "/posts", ExpectedStatus: 200},
+			{Path: "/posts?limit=10", ExpectedStatus: 206},
+			{Path: "/missing", ExpectedStatus: 404},
+			{Path: "/default"},
+		},
+	}
+	handler, err := NewHandler(suite)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedBody   string
+		expectedType   string
+	}{
+		{name: "root path", target: "/", expectedStatus: 200},
+		{name: "canned body and headers", target: "/users", expectedStatus: 200, expectedBody: `[{"id": 1}]`, expectedType: "application/json"},
+		{name: "path without query", target: "/posts", expectedStatus: 200},
+		{name: "more specific query wins", target: "/posts?limit=10", expectedStatus: 206},
+		{name: "non-matching query falls back", target: "/posts?limit=20", expectedStatus: 200},
+		{name: "expected error status", target: "/missing", expectedStatus: 404},
+		{name: "status defaults to 200", target: "/default", expectedStatus: 200},
+		{name: "unknown path", target: "/unknown", expectedStatus: 404, expectedBody: "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			body, err := io.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, string(body))
+			if tt.expectedType != "" {
+				assert.Equal(t, tt.expectedType, recorder.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestHandler_Latency(t *testing.T) {
+	handler, err := NewHandler(&config.TestSuite{
+		Endpoints: []config.Endpoint{
+			{Path: "/slow", ExpectedStatus: 200, Mock: &config.MockResponse{Latency: intPtr(50)}},
+		},
+	})
+	require.NoError(t, err)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := http.Get(server.URL + "/slow")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestNewHandler_InvalidPath(t *testing.T) {
+	_, err := NewHandler(&config.TestSuite{
+		Endpoints: []config.Endpoint{{Path: "/%zz"}},
+	})
+	assert.Error(t, err)
+}
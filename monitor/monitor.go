@@ -0,0 +1,318 @@
+// Package monitor provides a long-running mode that repeatedly executes a
+// test suite and reports changes in endpoint health.
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jgfranco17/dev-tooling-go/logging"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/outputs"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+// EndpointState is the rolling health state of a monitored endpoint.
+type EndpointState struct {
+	// Path is the configured path of the endpoint.
+	Path string
+
+	// Up is true if the most recent check of the endpoint passed.
+	Up bool
+
+	// Since is the time the endpoint entered its current state.
+	Since time.Time
+
+	// LastChecked is the time of the most recent check.
+	LastChecked time.Time
+
+	// LastResult is the result of the most recent check, or nil if the
+	// endpoint could not be reached.
+	LastResult *runner.TestResult
+
+	// Checks is the total number of checks of the endpoint.
+	Checks int
+
+	// Failures is the total number of failed checks of the endpoint.
+	Failures int
+}
+
+// schedule tracks when a group of endpoints is next due.
+type schedule struct {
+	endpoints []int
+	next      func(time.Time) time.Time
+	due       time.Time
+	immediate bool
+}
+
+// latestResult is the most recent result of an endpoint.
+type latestResult struct {
+	result      runner.TestResult
+	unreachable bool
+}
+
+// Monitor repeatedly executes a test suite, keeping the state of each
+// endpoint in memory and reporting only state transitions.
+type Monitor struct {
+	suite     *config.TestSuite
+	schedules []*schedule
+	mu        sync.RWMutex
+	states    []*EndpointState
+	latest    []*latestResult
+	listeners []func(runner.TestReport)
+	changes   []func(runner.TestReport)
+	reported  bool
+}
+
+// New creates a monitor for the suite. Endpoints without their own cron
+// schedule are checked on the given interval.
+func New(suite *config.TestSuite, interval time.Duration) (*Monitor, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("monitor interval must be positive, got %s", interval)
+	}
+	if err := runner.Validate(suite); err != nil {
+		return nil, err
+	}
+	m := &Monitor{
+		suite:  suite,
+		states: make([]*EndpointState, len(suite.Endpoints)),
+		latest: make([]*latestResult, len(suite.Endpoints)),
+	}
+
+	intervalGroup := &schedule{
+		next:      func(t time.Time) time.Time { return t.Add(interval) },
+		immediate: true,
+	}
+	for i, endpoint := range suite.Endpoints {
+		if endpoint.Schedule == "" {
+			intervalGroup.endpoints = append(intervalGroup.endpoints, i)
+			continue
+		}
+		parsed, err := cron.ParseStandard(endpoint.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule '%s' for endpoint %s: %w", endpoint.Schedule, endpoint.Path, err)
+		}
+		m.schedules = append(m.schedules, &schedule{endpoints: []int{i}, next: parsed.Next})
+	}
+	if len(intervalGroup.endpoints) > 0 {
+		m.schedules = append(m.schedules, intervalGroup)
+	}
+	return m, nil
+}
+
+// OnRun registers a function called with the report of every run. A run
+// only covers the endpoints that were due; use Report for the whole suite.
+func (m *Monitor) OnRun(listener func(runner.TestReport)) {
+	m.listeners = append(m.listeners, listener)
+}
+
+// OnChange registers a function called with the report of the whole suite
+// after the first run and whenever the set of failing endpoints changes
+// since, so that unchanged failures are reported once.
+func (m *Monitor) OnChange(listener func(runner.TestReport)) {
+	m.changes = append(m.changes, listener)
+}
+
+// States returns a snapshot of the current endpoint states. Endpoints that
+// have not been checked yet are omitted.
+func (m *Monitor) States() []EndpointState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	snapshot := make([]EndpointState, 0, len(m.states))
+	for _, state := range m.states {
+		if state != nil {
+			snapshot = append(snapshot, *state)
+		}
+	}
+	return snapshot
+}
+
+// Report returns the most recent result of every checked endpoint as a
+// single report, timestamped with the most recent check.
+func (m *Monitor) Report() runner.TestReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	report := runner.TestReport{Results: []runner.TestResult{}}
+	for i, latest := range m.latest {
+		if latest == nil {
+			continue
+		}
+		if latest.unreachable {
+			report.Unreachable = append(report.Unreachable, latest.result)
+		} else {
+			report.Results = append(report.Results, latest.result)
+		}
+		if checked := m.states[i].LastChecked; checked.After(report.Timestamp) {
+			report.Timestamp = checked
+		}
+	}
+	return report
+}
+
+// Run executes the suite on schedule until the context is cancelled.
+// Interval endpoints are checked immediately on start.
+func (m *Monitor) Run(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	if len(m.schedules) == 0 {
+		return fmt.Errorf("no endpoints to monitor")
+	}
+	runner.WarnInsecure(m.suite)
+	now := time.Now()
+	for _, s := range m.schedules {
+		if s.immediate {
+			s.due = now
+		} else {
+			s.due = s.next(now)
+		}
+	}
+
+	for {
+		nextDue := m.schedules[0].due
+		for _, s := range m.schedules[1:] {
+			if s.due.Before(nextDue) {
+				nextDue = s.due
+			}
+		}
+
+		timer := time.NewTimer(time.Until(nextDue))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info("Monitor shutting down")
+			return nil
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		var due []int
+		for _, s := range m.schedules {
+			if !s.due.After(now) {
+				due = append(due, s.endpoints...)
+				s.due = s.next(now)
+			}
+		}
+		if err := m.check(ctx, due); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// check executes the given endpoints once and updates their state.
+func (m *Monitor) check(ctx context.Context, indices []int) error {
+	logger := logging.FromContext(ctx)
+	subset := *m.suite
+	subset.Endpoints = make([]config.Endpoint, len(indices))
+	for i, index := range indices {
+		subset.Endpoints[i] = m.suite.Endpoints[index]
+	}
+
+	// Unreachable endpoints are reported by their transitions only.
+	report, err := runner.Run(ctx, &subset, runner.Options{Logger: logger})
+	if err != nil {
+		return fmt.Errorf("error running tests: %w", err)
+	}
+	logger.WithFields(logrus.Fields{
+		"endpoints": len(indices),
+		"results":   len(report.Results),
+	}).Debug("Monitor run completed")
+
+	byPath := make(map[string]runner.TestResult, len(report.Results))
+	for _, result := range report.Results {
+		byPath[result.Path] = result
+	}
+	unreachable := make(map[string]runner.TestResult, len(report.Unreachable))
+	for _, result := range report.Unreachable {
+		unreachable[result.Path] = result
+	}
+
+	m.mu.Lock()
+	failingBefore := m.failing()
+	for _, index := range indices {
+		path := m.suite.Endpoints[index].Path
+		var result *runner.TestResult
+		if r, ok := byPath[path]; ok {
+			result = &r
+			m.latest[index] = &latestResult{result: r}
+		} else {
+			m.latest[index] = &latestResult{result: unreachable[path], unreachable: true}
+		}
+		m.states[index] = transition(m.states[index], path, result, report.Timestamp)
+	}
+	changed := !m.reported || !slices.Equal(failingBefore, m.failing())
+	m.reported = true
+	m.mu.Unlock()
+
+	for _, listener := range m.listeners {
+		listener(report)
+	}
+	if changed && len(m.changes) > 0 {
+		suiteReport := m.Report()
+		for _, listener := range m.changes {
+			listener(suiteReport)
+		}
+	}
+	return nil
+}
+
+// failing returns the indices of the checked endpoints that are down. The
+// caller must hold the lock.
+func (m *Monitor) failing() []int {
+	var indices []int
+	for i, state := range m.states {
+		if state != nil && !state.Up {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// transition records a new check result, printing a message if the state of
+// the endpoint has changed.
+func transition(state *EndpointState, path string, result *runner.TestResult, checkedAt time.Time) *EndpointState {
+	up := result != nil && result.Passed
+	if state == nil {
+		if up {
+			outputs.PrintColoredMessage("green", "UP", "%s is up", path)
+		} else {
+			outputs.PrintColoredMessage("red", "DOWN", "%s is down: %s", path, describeFailure(result))
+		}
+		state = &EndpointState{Path: path, Up: up, Since: checkedAt}
+	} else if state.Up != up {
+		elapsed := checkedAt.Sub(state.Since).Round(time.Second)
+		if up {
+			outputs.PrintColoredMessage("green", "UP", "%s recovered after %s", path, elapsed)
+		} else {
+			outputs.PrintColoredMessage("red", "DOWN", "%s went down after %s: %s", path, elapsed, describeFailure(result))
+		}
+		state.Up = up
+		state.Since = checkedAt
+	}
+
+	state.Checks++
+	if !up {
+		state.Failures++
+	}
+	state.LastChecked = checkedAt
+	state.LastResult = result
+	return state
+}
+
+func describeFailure(result *runner.TestResult) string {
+	switch {
+	case result == nil:
+		return "unreachable"
+	case result.Message != "":
+		return result.Message
+	default:
+		return fmt.Sprintf("expected HTTP status %d but got %d", result.ExpectedStatus, result.HttpStatus)
+	}
+}
@@ -0,0 +1,318 @@
+package monitor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/dev-tooling-go/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+func newContextWithLogger(t *testing.T) context.Context {
+	t.Helper()
+	logger := logging.New(os.Stderr, logrus.WarnLevel)
+	return logging.WithContext(context.Background(), logger)
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		endpoints   []config.Endpoint
+		interval    time.Duration
+		expectedErr string
+		schedules   int
+	}{
+		{
+			name:      "interval endpoints share a schedule",
+			endpoints: []config.Endpoint{{Path: "/a"}, {Path: "/b"}},
+			interval:  time.Minute,
+			schedules: 1,
+		},
+		{
+			name:      "cron endpoints get their own schedule",
+			endpoints: []config.Endpoint{{Path: "/a"}, {Path: "/b", Schedule: "*/5 * * * *"}},
+			interval:  time.Minute,
+			schedules: 2,
+		},
+		{
+			name:        "invalid interval",
+			endpoints:   []config.Endpoint{{Path: "/a"}},
+			interval:    0,
+			expectedErr: "interval must be positive",
+		},
+		{
+			name:        "invalid cron expression",
+			endpoints:   []config.Endpoint{{Path: "/a", Schedule: "every tuesday"}},
+			interval:    time.Minute,
+			expectedErr: "invalid schedule 'every tuesday' for endpoint /a",
+		},
+		{
+			name:        "duplicate paths across schedules",
+			endpoints:   []config.Endpoint{{Path: "/a"}, {Path: "/a", Schedule: "*/5 * * * *"}},
+			interval:    time.Minute,
+			expectedErr: "invalid endpoint /a: path is used by another endpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(&config.TestSuite{Endpoints: tt.endpoints}, tt.interval)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, m.schedules, tt.schedules)
+		})
+	}
+}
+
+func TestMonitor_Run(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/toggle" && !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	suite := &config.TestSuite{
+		URL: server.URL,
+		Endpoints: []config.Endpoint{
+			{Path: "/stable", ExpectedStatus: 200},
+			{Path: "/toggle", ExpectedStatus: 200},
+			{Path: "/hourly", ExpectedStatus: 200, Schedule: "0 * * * *"},
+		},
+	}
+	m, err := New(suite, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(newContextWithLogger(t))
+	defer cancel()
+	runs := 0
+	m.OnRun(func(report runner.TestReport) {
+		runs++
+		switch runs {
+		case 2:
+			healthy.Store(false)
+		case 4:
+			healthy.Store(true)
+		case 6:
+			cancel()
+		}
+	})
+
+	require.NoError(t, m.Run(ctx))
+	assert.Equal(t, 6, runs)
+
+	states := m.States()
+	require.Len(t, states, 2, "the hourly endpoint should not have been checked yet")
+	assert.Equal(t, "/stable", states[0].Path)
+	assert.True(t, states[0].Up)
+	assert.Equal(t, 6, states[0].Checks)
+	assert.Zero(t, states[0].Failures)
+
+	assert.Equal(t, "/toggle", states[1].Path)
+	assert.True(t, states[1].Up)
+	assert.Equal(t, 2, states[1].Failures)
+	require.NotNil(t, states[1].LastResult)
+	assert.Equal(t, 200, states[1].LastResult.HttpStatus)
+}
+
+func TestMonitor_Report(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	suite := &config.TestSuite{
+		URL: server.URL,
+		Endpoints: []config.Endpoint{
+			{Path: "/broken", ExpectedStatus: 200},
+			{Path: "/hourly", ExpectedStatus: 200, Schedule: "0 * * * *"},
+			{Path: "/nightly", ExpectedStatus: 200, Schedule: "0 0 * * *"},
+		},
+	}
+	m, err := New(suite, time.Minute)
+	require.NoError(t, err)
+	ctx := newContextWithLogger(t)
+	assert.Empty(t, m.Report().Results)
+
+	var runs []runner.TestReport
+	m.OnRun(func(report runner.TestReport) {
+		runs = append(runs, report)
+	})
+	require.NoError(t, m.check(ctx, []int{0}))
+	require.NoError(t, m.check(ctx, []int{1}))
+
+	require.Len(t, runs, 2)
+	assert.True(t, runs[1].Passed(), "a run should only cover the endpoints that were due")
+	report := m.Report()
+	assert.False(t, report.Passed(), "the suite should still be failing")
+	require.Len(t, report.Results, 2, "endpoints that were not checked yet should be omitted")
+	assert.Equal(t, "/broken", report.Results[0].Path)
+	assert.Equal(t, "/hourly", report.Results[1].Path)
+	assert.Equal(t, runs[1].Timestamp, report.Timestamp)
+
+	m.suite.URL = "http://127.0.0.1:1"
+	require.NoError(t, m.check(ctx, []int{2}))
+	report = m.Report()
+	require.Len(t, report.Unreachable, 1)
+	assert.Equal(t, "/nightly", report.Unreachable[0].Path)
+}
+
+func TestMonitor_OnChange(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/nightly" || !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	suite := &config.TestSuite{
+		URL: server.URL,
+		Endpoints: []config.Endpoint{
+			{Path: "/health", ExpectedStatus: 200},
+			{Path: "/nightly", ExpectedStatus: 200, Schedule: "0 0 * * *"},
+		},
+	}
+	m, err := New(suite, time.Minute)
+	require.NoError(t, err)
+	ctx := newContextWithLogger(t)
+
+	var changes []runner.TestReport
+	m.OnChange(func(report runner.TestReport) {
+		changes = append(changes, report)
+	})
+	require.NoError(t, m.check(ctx, []int{0, 1}))
+	require.Len(t, changes, 1, "the first run should be reported")
+	assert.False(t, changes[0].Passed())
+
+	for range 3 {
+		require.NoError(t, m.check(ctx, []int{0}))
+	}
+	assert.Len(t, changes, 1, "an unchanged failure should not be reported again")
+
+	healthy.Store(false)
+	require.NoError(t, m.check(ctx, []int{0}))
+	require.NoError(t, m.check(ctx, []int{0}))
+	require.Len(t, changes, 2, "a new failure should be reported once")
+	assert.Len(t, changes[1].Results, 2, "the report should cover the whole suite")
+
+	healthy.Store(true)
+	require.NoError(t, m.check(ctx, []int{0}))
+	require.NoError(t, m.check(ctx, []int{0}))
+	require.Len(t, changes, 3, "a recovery should be reported once")
+	assert.False(t, changes[2].Passed(), "the nightly endpoint should still be failing")
+}
+
+// captureStdout returns everything written to stdout while f runs.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	f()
+
+	require.NoError(t, writer.Close())
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(output)
+}
+
+func TestMonitor_RunReportsOnlyTransitions(t *testing.T) {
+	suite := &config.TestSuite{
+		URL:       "http://127.0.0.1:1",
+		TLS:       &config.TLSConfig{InsecureSkipVerify: true},
+		Endpoints: []config.Endpoint{{Path: "/down", ExpectedStatus: 200}},
+	}
+	m, err := New(suite, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(newContextWithLogger(t))
+	defer cancel()
+	runs := 0
+	m.OnRun(func(report runner.TestReport) {
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+	})
+
+	output := captureStdout(t, func() {
+		require.NoError(t, m.Run(ctx))
+	})
+	assert.Equal(t, 1, strings.Count(output, "DOWN"), output)
+	assert.Equal(t, 1, strings.Count(output, "DISABLED"), "the insecure TLS warning should be printed once")
+	assert.NotContains(t, output, "UNREACHABLE")
+}
+
+func TestMonitor_RunCancelledBeforeStart(t *testing.T) {
+	suite := &config.TestSuite{
+		URL:       "https://example.com",
+		Endpoints: []config.Endpoint{{Path: "/hourly", Schedule: "0 * * * *"}},
+	}
+	m, err := New(suite, time.Minute)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(newContextWithLogger(t))
+	cancel()
+	assert.NoError(t, m.Run(ctx))
+	assert.Empty(t, m.States())
+}
+
+func TestTransition(t *testing.T) {
+	start := time.Now()
+	passed := &runner.TestResult{Passed: true, HttpStatus: 200, ExpectedStatus: 200}
+	failed := &runner.TestResult{HttpStatus: 500, ExpectedStatus: 200}
+
+	state := transition(nil, "/users", passed, start)
+	assert.True(t, state.Up)
+	assert.Equal(t, start, state.Since)
+
+	state = transition(state, "/users", passed, start.Add(time.Minute))
+	assert.Equal(t, start, state.Since, "repeated successes do not change state")
+
+	state = transition(state, "/users", failed, start.Add(2*time.Minute))
+	assert.False(t, state.Up)
+	assert.Equal(t, start.Add(2*time.Minute), state.Since)
+
+	state = transition(state, "/users", nil, start.Add(3*time.Minute))
+	assert.False(t, state.Up)
+	assert.Nil(t, state.LastResult)
+	assert.Equal(t, 4, state.Checks)
+	assert.Equal(t, 2, state.Failures)
+}
+
+func TestDescribeFailure(t *testing.T) {
+	assert.Equal(t, "unreachable", describeFailure(nil))
+	assert.Equal(t, "certificate expired", describeFailure(&runner.TestResult{Message: "certificate expired"}))
+	assert.Equal(t, "expected HTTP status 200 but got 503", describeFailure(&runner.TestResult{ExpectedStatus: 200, HttpStatus: 503}))
+}
@@ -0,0 +1,270 @@
+// Package notify sends webhook messages about the outcome of smoke test runs.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+// When values selecting the runs a target is notified of.
+const (
+	WhenFailure  string = "on-failure"
+	WhenRecovery string = "on-recovery"
+	WhenAlways   string = "always"
+)
+
+// defaultTimeout bounds each webhook request.
+const defaultTimeout = 10 * time.Second
+
+// Kind is the outcome of a run as reported in a notification.
+type Kind string
+
+const (
+	KindFailure  Kind = "failure"
+	KindRecovery Kind = "recovery"
+	KindSuccess  Kind = "success"
+)
+
+// Event is the data rendered by payload templates.
+type Event struct {
+	// Kind is the outcome of the run.
+	Kind Kind `json:"kind"`
+
+	// Suite is the name of the suite.
+	Suite string `json:"suite,omitempty"`
+
+	// Environment is the environment of the suite.
+	Environment string `json:"environment,omitempty"`
+
+	// URL is the base URL the suite was run against.
+	URL string `json:"url"`
+
+	// Timestamp is the start time of the run.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Title is a one-line summary of the run.
+	Title string `json:"title"`
+
+	// Failed lists the failing and unreachable endpoints.
+	Failed []runner.TestResult `json:"failed"`
+
+	// Passed is the number of passing endpoints.
+	Passed int `json:"passed"`
+
+	// Total is the number of tested endpoints.
+	Total int `json:"total"`
+}
+
+// target is a configured webhook with its parsed payload template.
+type target struct {
+	config.NotifyTarget
+	body *template.Template
+}
+
+// Notifier posts the outcome of runs of a suite to its configured targets.
+// It remembers the outcome of the previous run to detect recoveries.
+type Notifier struct {
+	suite   *config.TestSuite
+	targets []target
+	client  *http.Client
+
+	mu         sync.Mutex
+	lastFailed *bool
+}
+
+// New creates a notifier for the targets configured in the suite.
+func New(suite *config.TestSuite) (*Notifier, error) {
+	n := &Notifier{
+		suite:  suite,
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+	for _, conf := range suite.Notify {
+		t, err := newTarget(conf)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notify target %s: %w", targetName(conf), err)
+		}
+		n.targets = append(n.targets, t)
+	}
+	return n, nil
+}
+
+func newTarget(conf config.NotifyTarget) (target, error) {
+	if conf.URL == "" {
+		return target{}, fmt.Errorf("url is required")
+	}
+	switch conf.When {
+	case "":
+		conf.When = WhenFailure
+	case WhenFailure, WhenRecovery, WhenAlways:
+	default:
+		return target{}, fmt.Errorf("unknown when '%s'", conf.When)
+	}
+
+	source := conf.Body
+	switch {
+	case conf.Body != "" && conf.Template != "":
+		return target{}, fmt.Errorf("template and body are mutually exclusive")
+	case conf.Body == "":
+		builtin, ok := builtinTemplates[conf.Template]
+		if !ok {
+			return target{}, fmt.Errorf("unknown template '%s'", conf.Template)
+		}
+		source = builtin
+	}
+	body, err := template.New(targetName(conf)).Funcs(templateFuncs).Parse(source)
+	if err != nil {
+		return target{}, fmt.Errorf("error parsing body template: %w", err)
+	}
+	return target{NotifyTarget: conf, body: body}, nil
+}
+
+// SetLastOutcome records whether the run preceding the next notified run
+// failed, e.g. as read from the run history.
+func (n *Notifier) SetLastOutcome(failed bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.lastFailed = &failed
+}
+
+// Notify sends the outcome of the run to every target configured for it,
+// returning the errors of the targets that could not be notified.
+func (n *Notifier) Notify(ctx context.Context, report runner.TestReport) error {
+	event := n.newEvent(report)
+
+	n.mu.Lock()
+	recovered := event.Kind == KindSuccess && n.lastFailed != nil && *n.lastFailed
+	failed := event.Kind == KindFailure
+	n.lastFailed = &failed
+	n.mu.Unlock()
+	if recovered {
+		event.Kind = KindRecovery
+		event.Title = n.title(event)
+	}
+
+	var errs []error
+	for _, t := range n.targets {
+		if !t.matches(event.Kind) {
+			continue
+		}
+		if err := n.send(ctx, t, event); err != nil {
+			errs = append(errs, fmt.Errorf("error notifying %s: %w", targetName(t.NotifyTarget), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newEvent describes a report. Quarantined failures are listed but do not
+// make the run a failure.
+func (n *Notifier) newEvent(report runner.TestReport) Event {
+	event := Event{
+		Kind:        KindSuccess,
+		Suite:       n.suite.Name,
+		Environment: n.suite.Environment,
+		URL:         n.suite.URL,
+		Timestamp:   report.Timestamp,
+		Failed:      []runner.TestResult{},
+		Total:       len(report.Results) + len(report.Unreachable),
+	}
+	for _, result := range report.Results {
+		if result.Passed {
+			event.Passed++
+			continue
+		}
+		event.Failed = append(event.Failed, result)
+		if !result.Quarantined {
+			event.Kind = KindFailure
+		}
+	}
+	for _, result := range report.Unreachable {
+		event.Failed = append(event.Failed, result)
+		if !result.Quarantined {
+			event.Kind = KindFailure
+		}
+	}
+	event.Title = n.title(event)
+	return event
+}
+
+func (n *Notifier) title(event Event) string {
+	name := n.suite.Name
+	if name == "" {
+		name = n.suite.URL
+	}
+	if n.suite.Environment != "" {
+		name = fmt.Sprintf("%s (%s)", name, n.suite.Environment)
+	}
+	switch event.Kind {
+	case KindFailure:
+		return fmt.Sprintf("Smoke tests failed for %s: %d of %d endpoints failing", name, len(event.Failed), event.Total)
+	case KindRecovery:
+		return fmt.Sprintf("Smoke tests recovered for %s: %d of %d endpoints passing", name, event.Passed, event.Total)
+	default:
+		return fmt.Sprintf("Smoke tests passed for %s: %d of %d endpoints passing", name, event.Passed, event.Total)
+	}
+}
+
+func (t target) matches(kind Kind) bool {
+	switch t.When {
+	case WhenAlways:
+		return true
+	case WhenRecovery:
+		return kind == KindRecovery
+	default:
+		return kind == KindFailure
+	}
+}
+
+// send renders the payload of the target and posts it.
+func (n *Notifier) send(ctx context.Context, t target, event Event) error {
+	var body bytes.Buffer
+	if err := t.body.Execute(&body, event); err != nil {
+		return fmt.Errorf("error rendering body: %w", err)
+	}
+	if !json.Valid(body.Bytes()) {
+		return fmt.Errorf("rendered body is not valid JSON")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range t.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// targetName returns the name of the target, falling back to its host so
+// that secrets in webhook paths are not logged.
+func targetName(conf config.NotifyTarget) string {
+	if conf.Name != "" {
+		return conf.Name
+	}
+	parsed, err := url.Parse(conf.URL)
+	if err != nil || parsed.Host == "" {
+		return "webhook"
+	}
+	return parsed.Host
+}
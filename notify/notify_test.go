@@ -0,0 +1,231 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+// webhookStandIn records the requests posted to a local webhook.
+type webhookStandIn struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+func newWebhookStandIn(t *testing.T, status int) *webhookStandIn {
+	t.Helper()
+	w := &webhookStandIn{}
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.mu.Lock()
+		w.requests = append(w.requests, r)
+		w.bodies = append(w.bodies, string(body))
+		w.mu.Unlock()
+		rw.WriteHeader(status)
+	}))
+	t.Cleanup(w.Close)
+	return w
+}
+
+func (w *webhookStandIn) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.bodies)
+}
+
+var (
+	passingReport = runner.TestReport{
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Results: []runner.TestResult{
+			{Target: "https://example.com/users", Path: "/users", HttpStatus: 200, ExpectedStatus: 200, Passed: true},
+		},
+	}
+	failingReport = runner.TestReport{
+		Timestamp: time.Date(2024, 5, 1, 12, 5, 0, 0, time.UTC),
+		Results: []runner.TestResult{
+			{Target: "https://example.com/users", Path: "/users", HttpStatus: 503, ExpectedStatus: 200},
+		},
+		Unreachable: []runner.TestResult{
+			{Target: "https://example.com/orders", Path: "/orders", Message: "connection refused"},
+		},
+	}
+)
+
+func TestNotifier_When(t *testing.T) {
+	onFailure := newWebhookStandIn(t, http.StatusOK)
+	onRecovery := newWebhookStandIn(t, http.StatusOK)
+	always := newWebhookStandIn(t, http.StatusOK)
+	suite := &config.TestSuite{
+		Name: "checkout",
+		URL:  "https://example.com",
+		Notify: []config.NotifyTarget{
+			{URL: onFailure.URL},
+			{URL: onRecovery.URL, When: WhenRecovery},
+			{URL: always.URL, When: WhenAlways},
+		},
+	}
+	notifier, err := New(suite)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, notifier.Notify(ctx, passingReport))
+	assert.Equal(t, []int{0, 0, 1}, []int{onFailure.count(), onRecovery.count(), always.count()}, "a first passing run is not a recovery")
+
+	require.NoError(t, notifier.Notify(ctx, failingReport))
+	assert.Equal(t, []int{1, 0, 2}, []int{onFailure.count(), onRecovery.count(), always.count()})
+
+	require.NoError(t, notifier.Notify(ctx, passingReport))
+	assert.Equal(t, []int{1, 1, 3}, []int{onFailure.count(), onRecovery.count(), always.count()})
+
+	require.NoError(t, notifier.Notify(ctx, passingReport))
+	assert.Equal(t, []int{1, 1, 4}, []int{onFailure.count(), onRecovery.count(), always.count()})
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(always.bodies[1]), &event))
+	assert.Equal(t, KindFailure, event.Kind)
+	assert.Equal(t, "Smoke tests failed for checkout: 2 of 2 endpoints failing", event.Title)
+	assert.Len(t, event.Failed, 2)
+
+	require.NoError(t, json.Unmarshal([]byte(onRecovery.bodies[0]), &event))
+	assert.Equal(t, KindRecovery, event.Kind)
+}
+
+func TestNotifier_SetLastOutcome(t *testing.T) {
+	webhook := newWebhookStandIn(t, http.StatusOK)
+	suite := &config.TestSuite{
+		URL:    "https://example.com",
+		Notify: []config.NotifyTarget{{URL: webhook.URL, When: WhenRecovery}},
+	}
+	notifier, err := New(suite)
+	require.NoError(t, err)
+
+	notifier.SetLastOutcome(true)
+	require.NoError(t, notifier.Notify(context.Background(), passingReport))
+	assert.Equal(t, 1, webhook.count())
+}
+
+func TestNotifier_QuarantinedFailuresDoNotNotify(t *testing.T) {
+	webhook := newWebhookStandIn(t, http.StatusOK)
+	suite := &config.TestSuite{
+		URL:    "https://example.com",
+		Notify: []config.NotifyTarget{{URL: webhook.URL}},
+	}
+	notifier, err := New(suite)
+	require.NoError(t, err)
+
+	report := runner.TestReport{Results: []runner.TestResult{
+		{Target: "https://example.com/flaky", Path: "/flaky", HttpStatus: 500, ExpectedStatus: 200, Quarantined: true},
+	}}
+	require.NoError(t, notifier.Notify(context.Background(), report))
+	assert.Equal(t, 0, webhook.count())
+}
+
+func TestNotifier_BuiltinTemplates(t *testing.T) {
+	tests := []struct {
+		template string
+		expected []string
+	}{
+		{
+			template: "slack",
+			expected: []string{`"text": "Smoke tests failed for checkout (prod): 2 of 2 endpoints failing"`, `*https://example.com/users*\nexpected HTTP 200 but got 503`, `*https://example.com/orders*\nconnection refused`},
+		},
+		{
+			template: "teams",
+			expected: []string{`"type": "AdaptiveCard"`, `{"title": "https://example.com/users", "value": "expected HTTP 200 but got 503"}`, `{"title": "https://example.com/orders", "value": "connection refused"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			webhook := newWebhookStandIn(t, http.StatusOK)
+			suite := &config.TestSuite{
+				Name:        "checkout",
+				Environment: "prod",
+				URL:         "https://example.com",
+				Notify:      []config.NotifyTarget{{URL: webhook.URL, Template: tt.template}},
+			}
+			notifier, err := New(suite)
+			require.NoError(t, err)
+
+			require.NoError(t, notifier.Notify(context.Background(), failingReport))
+			require.Equal(t, 1, webhook.count())
+			assert.True(t, json.Valid([]byte(webhook.bodies[0])), "payload should be valid JSON: %s", webhook.bodies[0])
+			for _, fragment := range tt.expected {
+				assert.Contains(t, webhook.bodies[0], fragment)
+			}
+		})
+	}
+}
+
+func TestNotifier_CustomBodyAndHeaders(t *testing.T) {
+	webhook := newWebhookStandIn(t, http.StatusOK)
+	suite := &config.TestSuite{
+		Name: "checkout",
+		URL:  "https://example.com",
+		Notify: []config.NotifyTarget{{
+			URL:     webhook.URL,
+			Body:    `{"summary": {{ json .Title }}, "paths": [{{ range $i, $r := .Failed }}{{ if $i }}, {{ end }}{{ json $r.Path }}{{ end }}]}`,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		}},
+	}
+	notifier, err := New(suite)
+	require.NoError(t, err)
+
+	require.NoError(t, notifier.Notify(context.Background(), failingReport))
+	require.Equal(t, 1, webhook.count())
+	assert.JSONEq(t, `{"summary": "Smoke tests failed for checkout: 2 of 2 endpoints failing", "paths": ["/users", "/orders"]}`, webhook.bodies[0])
+	assert.Equal(t, "application/json", webhook.requests[0].Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", webhook.requests[0].Header.Get("Authorization"))
+}
+
+func TestNotifier_Errors(t *testing.T) {
+	rejecting := newWebhookStandIn(t, http.StatusForbidden)
+	suite := &config.TestSuite{
+		URL: "https://example.com",
+		Notify: []config.NotifyTarget{
+			{Name: "rejecting", URL: rejecting.URL},
+			{Name: "invalid-json", URL: rejecting.URL, Body: `{"title": {{ .Title }}}`},
+		},
+	}
+	notifier, err := New(suite)
+	require.NoError(t, err)
+
+	err = notifier.Notify(context.Background(), failingReport)
+	assert.ErrorContains(t, err, "error notifying rejecting: webhook responded with HTTP 403")
+	assert.ErrorContains(t, err, "error notifying invalid-json: rendered body is not valid JSON")
+	assert.Equal(t, 1, rejecting.count())
+}
+
+func TestNew_InvalidTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   config.NotifyTarget
+		expected string
+	}{
+		{name: "missing URL", target: config.NotifyTarget{Name: "chat"}, expected: "invalid notify target chat: url is required"},
+		{name: "unknown when", target: config.NotifyTarget{URL: "https://hooks.example.com/abc", When: "sometimes"}, expected: "invalid notify target hooks.example.com: unknown when 'sometimes'"},
+		{name: "unknown template", target: config.NotifyTarget{URL: "https://hooks.example.com", Template: "irc"}, expected: "unknown template 'irc'"},
+		{name: "template and body", target: config.NotifyTarget{URL: "https://hooks.example.com", Template: "slack", Body: "{}"}, expected: "template and body are mutually exclusive"},
+		{name: "invalid body", target: config.NotifyTarget{URL: "https://hooks.example.com", Body: "{{ .Title"}, expected: "error parsing body template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&config.TestSuite{Notify: []config.NotifyTarget{tt.target}})
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+// defaultTemplate renders the event as plain JSON.
+const defaultTemplate = `{{ json . }}`
+
+// slackTemplate renders a Slack incoming webhook message.
+const slackTemplate = `{
+  "text": {{ json .Title }},
+  "blocks": [
+    {"type": "header", "text": {"type": "plain_text", "text": {{ json .Title }}}}
+    {{- range .Failed }},
+    {"type": "section", "text": {"type": "mrkdwn", "text": {{ json (printf "*%s*\n%s" .Target (reason .)) }}}}
+    {{- end }}
+  ]
+}`
+
+// teamsTemplate renders an Adaptive Card for a Microsoft Teams workflow
+// webhook.
+const teamsTemplate = `{
+  "type": "message",
+  "attachments": [
+    {
+      "contentType": "application/vnd.microsoft.card.adaptive",
+      "content": {
+        "$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+        "type": "AdaptiveCard",
+        "version": "1.4",
+        "body": [
+          {"type": "TextBlock", "size": "Medium", "weight": "Bolder", "wrap": true, "text": {{ json .Title }}},
+          {"type": "FactSet", "facts": [
+            {{- range $i, $result := .Failed }}{{ if $i }},{{ end }}
+            {"title": {{ json $result.Target }}, "value": {{ json (reason $result) }}}
+            {{- end }}
+          ]}
+        ]
+      }
+    }
+  ]
+}`
+
+// builtinTemplates maps template names to their payload templates.
+var builtinTemplates = map[string]string{
+	"":      defaultTemplate,
+	"slack": slackTemplate,
+	"teams": teamsTemplate,
+}
+
+// templateFuncs are the functions available to payload templates.
+var templateFuncs = template.FuncMap{
+	"json":   toJSON,
+	"reason": reason,
+}
+
+// toJSON encodes a value as JSON, e.g. to quote a string.
+func toJSON(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// reason describes why a result failed.
+func reason(result runner.TestResult) string {
+	switch {
+	case result.Passed:
+		return "passed"
+	case result.Message != "":
+		return result.Message
+	default:
+		return fmt.Sprintf("expected HTTP %d but got %d", result.ExpectedStatus, result.HttpStatus)
+	}
+}
@@ -2,7 +2,9 @@ package outputs
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/fatih/color"
 )
@@ -39,3 +41,12 @@ func PrintError(text string, args ...any) {
 	message := fmt.Sprintf(text, args...)
 	fmt.Printf("[%s] %s\n", red("ERROR"), message)
 }
+
+func PrintTable(headers []string, rows [][]string) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	writer.Flush()
+}
@@ -0,0 +1,69 @@
+package smokesweep
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+)
+
+// handlerBaseURL is the base URL used for suites run against a handler
+// without a URL of their own.
+const handlerBaseURL string = "http://smokesweep.test"
+
+// HandlerTransport returns a round tripper serving every request with the
+// handler in-process, without opening a port.
+func HandlerTransport(handler http.Handler) http.RoundTripper {
+	return handlerTransport{handler: handler}
+}
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	inbound := req.Clone(req.Context())
+	inbound.RequestURI = req.URL.RequestURI()
+	inbound.RemoteAddr = "192.0.2.1:1234"
+	if inbound.Body == nil {
+		inbound.Body = http.NoBody
+	}
+
+	recorder := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t.handler.ServeHTTP(recorder, inbound)
+	}()
+	select {
+	case <-done:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// WithHandler runs the suites against the handler in-process instead of
+// over the network. Suites without a URL are given a placeholder one.
+func WithHandler(handler http.Handler) Option {
+	return func(r *Runner) error {
+		if handler == nil {
+			return errors.New("handler must not be nil")
+		}
+		r.client = &http.Client{Transport: HandlerTransport(handler)}
+		r.handler = true
+		return nil
+	}
+}
+
+// RunHandler runs the suite against the handler in-process.
+func RunHandler(ctx context.Context, suite *Suite, handler http.Handler, opts ...Option) (*Report, error) {
+	r, err := New(append(opts, WithHandler(handler))...)
+	if err != nil {
+		return nil, err
+	}
+	return r.Run(ctx, suite)
+}
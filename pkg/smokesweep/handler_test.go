@@ -0,0 +1,99 @@
+package smokesweep
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"limit": %q}`, r.URL.Query().Get("limit"))
+	})
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	return mux
+}
+
+func TestHandlerTransport(t *testing.T) {
+	client := &http.Client{Transport: HandlerTransport(newTestMux())}
+
+	resp, err := client.Get("http://example.com/users?limit=5")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "/users", resp.Request.URL.Path)
+
+	resp, err = client.Get("http://example.com/missing")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/slow", nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRunHandler(t *testing.T) {
+	timeout := 50
+	suite := &Suite{
+		Endpoints: []Endpoint{
+			{Path: "/users?limit=5", ExpectedStatus: 200},
+			{Path: "/broken", ExpectedStatus: 200},
+			{Path: "/slow", ExpectedStatus: 200, Timeout: &timeout},
+		},
+	}
+
+	report, err := RunHandler(context.Background(), suite, newTestMux())
+	require.NoError(t, err)
+	require.Len(t, report.Results, 2)
+	assert.True(t, report.Results[0].Passed)
+	assert.Equal(t, handlerBaseURL+"/users?limit=5", report.Results[0].Target)
+	assert.JSONEq(t, `{"limit": "5"}`, string(report.Results[0].Body))
+	assert.Equal(t, http.StatusInternalServerError, report.Results[1].HttpStatus)
+	require.Len(t, report.Unreachable, 1, "endpoint timeouts should apply in-process")
+	assert.Equal(t, "/slow", report.Unreachable[0].Path)
+	assert.Empty(t, suite.URL, "the suite should not be modified")
+
+	_, err = RunHandler(context.Background(), suite, nil)
+	assert.EqualError(t, err, "handler must not be nil")
+}
+
+func TestRunHandlerT(t *testing.T) {
+	suite := &Suite{
+		URL: "https://api.example.com",
+		Endpoints: []Endpoint{
+			{Name: "list users", Path: "/users", ExpectedStatus: 200},
+			{Path: "/broken", ExpectedStatus: 200, Quarantine: true},
+		},
+	}
+
+	var report *Report
+	capture := ReporterFunc(func(ctx context.Context, r *Report) error {
+		report = r
+		return nil
+	})
+	t.Run("suite", func(t *testing.T) {
+		RunHandlerT(t, suite, newTestMux(), WithReporter(capture))
+	})
+	require.NotNil(t, report)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "https://api.example.com/users", report.Results[0].Target)
+	assert.True(t, report.Passed(), "quarantined failures should not fail the report")
+}
@@ -0,0 +1,194 @@
+package smokesweep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+// Reporter receives the report of every completed run.
+type Reporter interface {
+	Report(ctx context.Context, report *Report) error
+}
+
+// ReporterFunc adapts a function to the Reporter interface.
+type ReporterFunc func(ctx context.Context, report *Report) error
+
+// Report calls f.
+func (f ReporterFunc) Report(ctx context.Context, report *Report) error {
+	return f(ctx, report)
+}
+
+// JSONReporter returns a reporter writing each report as indented JSON.
+func JSONReporter(w io.Writer) Reporter {
+	return ReporterFunc(func(ctx context.Context, report *Report) error {
+		return report.WriteJSON(w)
+	})
+}
+
+// Option configures a Runner.
+type Option func(*Runner) error
+
+// WithHTTPClient sends the requests with the client instead of one built
+// from the TLS settings of the suite. Endpoint timeouts override the timeout
+// of the client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Runner) error {
+		if client == nil {
+			return errors.New("http client must not be nil")
+		}
+		r.client = client
+		return nil
+	}
+}
+
+// WithReporter adds a reporter called with the report of every run.
+func WithReporter(reporter Reporter) Option {
+	return func(r *Runner) error {
+		if reporter == nil {
+			return errors.New("reporter must not be nil")
+		}
+		r.reporters = append(r.reporters, reporter)
+		return nil
+	}
+}
+
+// WithLogger sends progress logs to the logger. Logs are discarded by
+// default.
+func WithLogger(logger *logrus.Logger) Option {
+	return func(r *Runner) error {
+		r.logger = logger
+		return nil
+	}
+}
+
+// WithConcurrency sets the maximum number of requests in flight.
+func WithConcurrency(n int) Option {
+	return func(r *Runner) error {
+		if n < 1 {
+			return fmt.Errorf("concurrency must be at least 1, got %d", n)
+		}
+		r.concurrency = n
+		return nil
+	}
+}
+
+// WithTags restricts runs to endpoints with at least one of the tags.
+func WithTags(tags ...string) Option {
+	return func(r *Runner) error {
+		r.tags = append(r.tags, tags...)
+		return nil
+	}
+}
+
+// WithFilter restricts runs to endpoints for which keep returns true.
+// Multiple filters must all keep an endpoint.
+func WithFilter(keep func(Endpoint) bool) Option {
+	return func(r *Runner) error {
+		if keep == nil {
+			return errors.New("filter must not be nil")
+		}
+		r.filters = append(r.filters, keep)
+		return nil
+	}
+}
+
+// WithFailFast stops runs on the first failure of an endpoint that is not
+// quarantined, returning a *FailureError.
+func WithFailFast() Option {
+	return func(r *Runner) error {
+		r.failFast = true
+		return nil
+	}
+}
+
+// Runner executes smoke test suites. It is safe for concurrent use.
+type Runner struct {
+	client      *http.Client
+	reporters   []Reporter
+	logger      *logrus.Logger
+	concurrency int
+	tags        []string
+	filters     []func(Endpoint) bool
+	failFast    bool
+	handler     bool
+}
+
+// New creates a runner with the given options.
+func New(opts ...Option) (*Runner, error) {
+	r := &Runner{concurrency: runner.DefaultConcurrency}
+	for _, opt := range opts {
+		if err := opt(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Run executes the endpoints of the suite selected by the filters of the
+// runner and passes the report to every reporter. The suite is not
+// modified. With fail-fast enabled, the error of a failing endpoint is a
+// *FailureError and no report is returned.
+func (r *Runner) Run(ctx context.Context, suite *Suite) (*Report, error) {
+	report, err := r.execute(ctx, r.selectEndpoints(suite), r.failFast)
+	if err != nil {
+		return nil, err
+	}
+	return report, r.report(ctx, report)
+}
+
+// execute runs the endpoints of an already selected suite.
+func (r *Runner) execute(ctx context.Context, selected *Suite, failFast bool) (*Report, error) {
+	report, err := runner.Run(ctx, selected, runner.Options{
+		FailFast:    failFast,
+		Concurrency: r.concurrency,
+		Client:      r.client,
+		Logger:      r.logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// report passes the report to every reporter.
+func (r *Runner) report(ctx context.Context, report *Report) error {
+	var errs []error
+	for _, reporter := range r.reporters {
+		if err := reporter.Report(ctx, report); err != nil {
+			errs = append(errs, fmt.Errorf("error reporting results: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// selectEndpoints returns a copy of the suite with the endpoints kept by the
+// tags and filters of the runner.
+func (r *Runner) selectEndpoints(suite *Suite) *Suite {
+	selected := suite.FilterTags(r.tags)
+	if r.handler && selected.URL == "" {
+		selected.URL = handlerBaseURL
+	}
+	if len(r.filters) == 0 {
+		return selected
+	}
+	endpoints := make([]config.Endpoint, 0, len(selected.Endpoints))
+	for _, endpoint := range selected.Endpoints {
+		keep := true
+		for _, filter := range r.filters {
+			keep = keep && filter(endpoint)
+		}
+		if keep {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	selected.Endpoints = endpoints
+	return selected
+}
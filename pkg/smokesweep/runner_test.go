@@ -0,0 +1,222 @@
+package smokesweep
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout returns everything written to stdout while f runs.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	f()
+
+	require.NoError(t, writer.Close())
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(output)
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunner_RunWithoutSideEffects(t *testing.T) {
+	server := newTestServer(t)
+	suite := &Suite{
+		URL: server.URL,
+		Endpoints: []Endpoint{
+			{Path: "/users", ExpectedStatus: 200},
+			{Path: "/broken", ExpectedStatus: 200},
+		},
+	}
+	unreachable := &Suite{
+		URL:       "http://127.0.0.1:1",
+		Endpoints: []Endpoint{{Path: "/down", ExpectedStatus: 200}},
+	}
+	r, err := New()
+	require.NoError(t, err)
+
+	var report, unreachableReport *Report
+	output := captureStdout(t, func() {
+		// The context deliberately carries no logger.
+		report, err = r.Run(context.Background(), suite)
+		require.NoError(t, err)
+		unreachableReport, err = r.Run(context.Background(), unreachable)
+		require.NoError(t, err)
+	})
+
+	assert.Empty(t, output, "the library should not write to stdout")
+	require.Len(t, report.Results, 2)
+	assert.True(t, report.Results[0].Passed)
+	assert.False(t, report.Results[1].Passed)
+	assert.False(t, report.Passed())
+	require.Len(t, unreachableReport.Unreachable, 1)
+	assert.Contains(t, unreachableReport.Unreachable[0].Message, "failed to reach target")
+}
+
+func TestRunner_FailFast(t *testing.T) {
+	server := newTestServer(t)
+	suite := &Suite{
+		URL:       server.URL,
+		Endpoints: []Endpoint{{Path: "/broken", ExpectedStatus: 200}},
+	}
+	r, err := New(WithFailFast())
+	require.NoError(t, err)
+
+	report, err := r.Run(context.Background(), suite)
+	assert.Nil(t, report)
+	var failure *FailureError
+	require.True(t, errors.As(err, &failure))
+	assert.Equal(t, http.StatusInternalServerError, failure.Result.HttpStatus)
+	assert.Equal(t, "/broken", failure.Result.Path)
+
+	r, err = New(WithFailFast())
+	require.NoError(t, err)
+	_, err = r.Run(context.Background(), &Suite{
+		URL:       "http://127.0.0.1:1",
+		Endpoints: []Endpoint{{Path: "/down", ExpectedStatus: 200}},
+	})
+	require.True(t, errors.As(err, &failure))
+	assert.Error(t, failure.Err, "unreachable failures should wrap the transport error")
+	assert.Zero(t, failure.Result.HttpStatus)
+}
+
+func TestRunner_Filters(t *testing.T) {
+	server := newTestServer(t)
+	suite := &Suite{
+		URL: server.URL,
+		Endpoints: []Endpoint{
+			{Path: "/users", ExpectedStatus: 200, Tags: []string{"critical"}},
+			{Path: "/orders", ExpectedStatus: 200, Tags: []string{"critical", "slow"}},
+			{Path: "/search", ExpectedStatus: 200},
+		},
+	}
+	r, err := New(
+		WithTags("critical"),
+		WithFilter(func(e Endpoint) bool { return !strings.HasPrefix(e.Path, "/orders") }),
+	)
+	require.NoError(t, err)
+
+	report, err := r.Run(context.Background(), suite)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, "/users", report.Results[0].Path)
+	assert.Len(t, suite.Endpoints, 3, "the suite should not be modified")
+}
+
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRunner_Options(t *testing.T) {
+	server := newTestServer(t)
+	suite := &Suite{
+		URL:       server.URL,
+		Repeat:    3,
+		Endpoints: []Endpoint{{Path: "/users", ExpectedStatus: 200}},
+	}
+	transport := &countingTransport{}
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	logger.SetLevel(logrus.InfoLevel)
+	var reported *Report
+	var jsonReport bytes.Buffer
+
+	r, err := New(
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithLogger(logger),
+		WithConcurrency(1),
+		WithReporter(ReporterFunc(func(ctx context.Context, report *Report) error {
+			reported = report
+			return nil
+		})),
+		WithReporter(JSONReporter(&jsonReport)),
+	)
+	require.NoError(t, err)
+
+	report, err := r.Run(context.Background(), suite)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), transport.requests.Load())
+	assert.Contains(t, logs.String(), "Pinging target")
+	assert.Same(t, report, reported)
+	assert.Contains(t, jsonReport.String(), `"path": "/users"`)
+}
+
+func TestRunner_ReporterError(t *testing.T) {
+	server := newTestServer(t)
+	suite := &Suite{
+		URL:       server.URL,
+		Endpoints: []Endpoint{{Path: "/users", ExpectedStatus: 200}},
+	}
+	r, err := New(WithReporter(ReporterFunc(func(ctx context.Context, report *Report) error {
+		return errors.New("disk full")
+	})))
+	require.NoError(t, err)
+
+	report, err := r.Run(context.Background(), suite)
+	assert.NotNil(t, report, "the report should be returned even if a reporter fails")
+	assert.EqualError(t, err, "error reporting results: disk full")
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		option   Option
+		expected string
+	}{
+		{name: "nil client", option: WithHTTPClient(nil), expected: "http client must not be nil"},
+		{name: "nil reporter", option: WithReporter(nil), expected: "reporter must not be nil"},
+		{name: "zero concurrency", option: WithConcurrency(0), expected: "concurrency must be at least 1, got 0"},
+		{name: "nil filter", option: WithFilter(nil), expected: "filter must not be nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.option)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestLoadSuite(t *testing.T) {
+	suite, err := LoadSuite(strings.NewReader("url: https://example.com\nendpoints:\n  - path: /health\n    expected-status: 200\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", suite.URL)
+	require.Len(t, suite.Endpoints, 1)
+
+	_, err = LoadSuiteFile("non-existent.yaml")
+	assert.ErrorContains(t, err, "error opening suite file")
+}
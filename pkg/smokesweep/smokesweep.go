@@ -0,0 +1,68 @@
+// Package smokesweep is the public API for running smoke test suites from Go
+// programs. Unlike the CLI, it never writes to stdout and does not require a
+// logger in the context.
+//
+//	suite, err := smokesweep.LoadSuiteFile("smoke.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	r, err := smokesweep.New(smokesweep.WithTags("critical"), smokesweep.WithFailFast())
+//	if err != nil {
+//		return err
+//	}
+//	report, err := r.Run(ctx, suite)
+package smokesweep
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+type (
+	// Suite is a smoke test suite.
+	Suite = config.TestSuite
+
+	// Endpoint is a single endpoint of a suite.
+	Endpoint = config.Endpoint
+
+	// Report is the outcome of a run.
+	Report = runner.TestReport
+
+	// Result is the outcome of a single endpoint.
+	Result = runner.TestResult
+
+	// FailureError is returned by a fail-fast run for the first failing
+	// endpoint.
+	FailureError = runner.FailureError
+
+	// Check probes endpoints of one type.
+	Check = runner.Check
+
+	// CheckRequest is a single probe of an endpoint.
+	CheckRequest = runner.CheckRequest
+)
+
+// RegisterCheck makes a check available for endpoints whose type field is
+// typ. It panics if the type is already registered.
+func RegisterCheck(typ string, check Check) {
+	runner.RegisterCheck(typ, check)
+}
+
+// LoadSuite parses a suite from YAML.
+func LoadSuite(r io.Reader) (*Suite, error) {
+	return config.Load(r)
+}
+
+// LoadSuiteFile parses a suite from a YAML file.
+func LoadSuiteFile(path string) (*Suite, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening suite file: %w", err)
+	}
+	defer file.Close()
+	return LoadSuite(file)
+}
@@ -0,0 +1,132 @@
+package smokesweep
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// bodySnippetLength is the maximum number of response body bytes included
+// in a test failure.
+const bodySnippetLength int = 256
+
+// deadlineGrace is how long before the deadline of the test binary, set by
+// -timeout, requests are cancelled so that the failure is reported cleanly.
+const deadlineGrace = time.Second
+
+// RunT runs each endpoint of the suite selected by the options as a parallel
+// subtest of t, named after the endpoint, so that -run and -parallel apply to
+// individual endpoints. Failing endpoints fail their subtest and quarantined
+// failing endpoints are skipped. Reporters receive the combined report of
+// the endpoints that ran once every subtest has finished. Fail-fast has no
+// effect, as every endpoint runs on its own.
+func RunT(t *testing.T, suite *Suite, opts ...Option) {
+	t.Helper()
+	r, err := New(opts...)
+	if err != nil {
+		t.Fatalf("error configuring runner: %v", err)
+	}
+	r.RunT(t, suite)
+}
+
+// RunHandlerT runs the suite against the handler in-process like RunT.
+func RunHandlerT(t *testing.T, suite *Suite, handler http.Handler, opts ...Option) {
+	t.Helper()
+	RunT(t, suite, append(opts, WithHandler(handler))...)
+}
+
+// RunT runs each endpoint of the suite as a parallel subtest of t, like the
+// RunT function.
+func (r *Runner) RunT(t *testing.T, suite *Suite) {
+	t.Helper()
+	selected := r.selectEndpoints(suite)
+	reports := make([]*Report, len(selected.Endpoints))
+	started := time.Now()
+	t.Cleanup(func() {
+		combined := &Report{Timestamp: started, Results: []Result{}}
+		for _, report := range reports {
+			if report != nil {
+				combined.Results = append(combined.Results, report.Results...)
+				combined.Unreachable = append(combined.Unreachable, report.Unreachable...)
+			}
+		}
+		// The context of t is cancelled before its cleanup functions run.
+		if err := r.report(context.WithoutCancel(t.Context()), combined); err != nil {
+			t.Error(err)
+		}
+	})
+
+	for i, endpoint := range selected.Endpoints {
+		single := *selected
+		single.Endpoints = []Endpoint{endpoint}
+		t.Run(subtestName(endpoint), func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+			if deadline, ok := t.Deadline(); ok {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, deadline.Add(-deadlineGrace))
+				defer cancel()
+			}
+			report, err := r.execute(ctx, &single, false)
+			if err != nil {
+				t.Fatalf("error running endpoint: %v", err)
+			}
+			reports[i] = report
+			for _, result := range append(report.Results, report.Unreachable...) {
+				checkResult(t, result)
+			}
+		})
+	}
+}
+
+// checkResult fails or skips t if the result did not pass.
+func checkResult(t *testing.T, result Result) {
+	t.Helper()
+	if result.Passed {
+		return
+	}
+	if result.Quarantined {
+		t.Skipf("quarantined: %s", describeFailure(result))
+	}
+	t.Errorf("%s", describeFailure(result))
+}
+
+// subtestName returns the subtest name of an endpoint, without the leading
+// slash of a path so that it does not start an empty -run level.
+func subtestName(endpoint Endpoint) string {
+	return strings.TrimPrefix(endpoint.DisplayName(), "/")
+}
+
+// describeFailure describes why a result failed, including the start of the
+// response body if there is one.
+func describeFailure(result Result) string {
+	var description string
+	switch {
+	case result.HttpStatus == 0:
+		description = fmt.Sprintf("%s: %s", result.Target, result.Message)
+	case result.Message != "":
+		description = fmt.Sprintf("GET %s: %s (HTTP %d, expected %d)", result.Target, result.Message, result.HttpStatus, result.ExpectedStatus)
+	default:
+		description = fmt.Sprintf("GET %s: expected HTTP %d but got %d", result.Target, result.ExpectedStatus, result.HttpStatus)
+	}
+	if snippet := bodySnippet(result.Body); snippet != "" {
+		description += "\nbody: " + snippet
+	}
+	return description
+}
+
+// bodySnippet returns the start of a response body as text.
+func bodySnippet(body []byte) string {
+	if len(body) <= bodySnippetLength {
+		return strings.ToValidUTF8(string(body), "?")
+	}
+	end := bodySnippetLength
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+	return strings.ToValidUTF8(string(body[:end]), "?") + "..."
+}
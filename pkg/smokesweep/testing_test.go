@@ -0,0 +1,111 @@
+package smokesweep
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunT(t *testing.T) {
+	server := newTestServer(t)
+	suite := &Suite{
+		URL: server.URL,
+		Endpoints: []Endpoint{
+			{Name: "list users", Path: "/users", ExpectedStatus: 200},
+			{Path: "/health", ExpectedStatus: 200},
+			{Path: "/broken", ExpectedStatus: 200, Quarantine: true},
+		},
+	}
+
+	var reports atomic.Int32
+	var report *Report
+	var ctxErr error
+	capture := ReporterFunc(func(ctx context.Context, r *Report) error {
+		reports.Add(1)
+		report = r
+		ctxErr = ctx.Err()
+		return nil
+	})
+	t.Run("suite", func(t *testing.T) {
+		RunT(t, suite, WithReporter(capture))
+	})
+
+	assert.Equal(t, int32(1), reports.Load(), "reporters should receive a single combined report")
+	assert.NoError(t, ctxErr, "reporters should receive a live context")
+	require.NotNil(t, report)
+	require.Len(t, report.Results, 3)
+	assert.Equal(t, "/users", report.Results[0].Path, "results should keep the order of the suite")
+	assert.Equal(t, "/health", report.Results[1].Path)
+	assert.False(t, report.Results[2].Passed)
+	assert.True(t, report.Passed(), "quarantined failures should not fail the report")
+}
+
+func TestRunT_SelectedEndpoints(t *testing.T) {
+	server := newTestServer(t)
+	suite := &Suite{
+		URL: server.URL,
+		Endpoints: []Endpoint{
+			{Path: "/users", ExpectedStatus: 200, Tags: []string{"critical"}},
+			{Path: "/broken", ExpectedStatus: 200},
+		},
+	}
+
+	var report *Report
+	capture := ReporterFunc(func(ctx context.Context, r *Report) error {
+		report = r
+		return nil
+	})
+	t.Run("suite", func(t *testing.T) {
+		RunT(t, suite, WithTags("critical"), WithReporter(capture))
+	})
+
+	require.NotNil(t, report)
+	require.Len(t, report.Results, 1, "only endpoints selected by the options should run")
+	assert.Equal(t, "/users", report.Results[0].Path)
+}
+
+func TestSubtestName(t *testing.T) {
+	assert.Equal(t, "list users", subtestName(Endpoint{Name: "list users", Path: "/users"}))
+	assert.Equal(t, "users/42", subtestName(Endpoint{Path: "/users/42"}))
+}
+
+func TestDescribeFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   Result
+		expected string
+	}{
+		{
+			name:     "unexpected status",
+			result:   Result{Target: "https://example.com/users", ExpectedStatus: 200, HttpStatus: 503},
+			expected: "GET https://example.com/users: expected HTTP 200 but got 503",
+		},
+		{
+			name:     "failed assertion with body",
+			result:   Result{Target: "https://example.com/users", ExpectedStatus: 200, HttpStatus: 200, Message: "missing field id", Body: []byte(`{"name": "x"}`)},
+			expected: "GET https://example.com/users: missing field id (HTTP 200, expected 200)\nbody: {\"name\": \"x\"}",
+		},
+		{
+			name:     "unreachable",
+			result:   Result{Target: "https://example.com/users", Message: "connection refused"},
+			expected: "https://example.com/users: connection refused",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, describeFailure(tc.result))
+		})
+	}
+}
+
+func TestBodySnippet(t *testing.T) {
+	assert.Equal(t, "", bodySnippet(nil))
+	assert.Equal(t, "short", bodySnippet([]byte("short")))
+
+	long := bodySnippet([]byte(strings.Repeat("a", bodySnippetLength-1) + "é" + "tail"))
+	assert.Equal(t, strings.Repeat("a", bodySnippetLength-1)+"...", long, "multi-byte characters should not be split")
+}
@@ -0,0 +1,188 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/jgfranco17/smokesweep/outputs"
+)
+
+// DefaultLatencyRegression is the percentage by which an endpoint must slow
+// down compared to the baseline to be reported as a latency regression.
+const DefaultLatencyRegression float64 = 50
+
+// ChangeKind classifies a change in an endpoint since the baseline report.
+type ChangeKind string
+
+const (
+	// ChangeRegression is an endpoint that passed in the baseline but fails now.
+	ChangeRegression ChangeKind = "regression"
+
+	// ChangeNewFailure is a failing endpoint that is absent from the baseline.
+	ChangeNewFailure ChangeKind = "new-failure"
+
+	// ChangeRecovery is an endpoint that failed in the baseline but passes now.
+	ChangeRecovery ChangeKind = "recovery"
+
+	// ChangeLatencyRegression is a passing endpoint that slowed down beyond
+	// the latency regression threshold.
+	ChangeLatencyRegression ChangeKind = "latency-regression"
+)
+
+// BaselineChange is a change in the outcome of an endpoint since the
+// baseline report.
+type BaselineChange struct {
+	// Target is the URL of the endpoint.
+	Target string `json:"target"`
+
+	// Path is the configured path of the endpoint.
+	Path string `json:"path"`
+
+	// Kind classifies the change.
+	Kind ChangeKind `json:"kind"`
+
+	// Detail describes the change.
+	Detail string `json:"detail"`
+}
+
+// IsRegression reports whether the change is for the worse.
+func (c BaselineChange) IsRegression() bool {
+	return c.Kind != ChangeRecovery
+}
+
+// ReadReport decodes a test report previously written with WriteJSON.
+func ReadReport(r io.Reader) (*TestReport, error) {
+	var report TestReport
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("error decoding test report: %w", err)
+	}
+	return &report, nil
+}
+
+// CompareBaseline records the changes of the report since the baseline,
+// matching endpoints by path. Passing endpoints that slowed down by more than
+// latencyRegression percent are reported as latency regressions.
+func (tr *TestReport) CompareBaseline(baseline *TestReport, latencyRegression float64) {
+	previous := make(map[string]TestResult, len(baseline.Results)+len(baseline.Unreachable))
+	unreachable := make(map[string]bool, len(baseline.Unreachable))
+	for _, result := range baseline.Unreachable {
+		previous[result.Path] = result
+		unreachable[result.Path] = true
+	}
+	for _, result := range baseline.Results {
+		previous[result.Path] = result
+		delete(unreachable, result.Path)
+	}
+
+	tr.BaselineChanges = []BaselineChange{}
+	record := func(result TestResult, kind ChangeKind, format string, args ...any) {
+		tr.BaselineChanges = append(tr.BaselineChanges, BaselineChange{
+			Target: result.Target,
+			Path:   result.Path,
+			Kind:   kind,
+			Detail: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, result := range tr.Results {
+		before, ok := previous[result.Path]
+		switch {
+		case !ok:
+			if !result.Passed {
+				record(result, ChangeNewFailure, "failing and not in baseline: %s", describeOutcome(result))
+			}
+		case before.Passed && !result.Passed:
+			record(result, ChangeRegression, "passed in baseline, now %s", describeOutcome(result))
+		case !before.Passed && result.Passed && unreachable[result.Path]:
+			record(result, ChangeRecovery, "was unreachable in baseline, now passes")
+		case !before.Passed && result.Passed:
+			record(result, ChangeRecovery, "was %s in baseline, now passes", describeOutcome(before))
+		case before.Passed && result.Passed && before.Duration > 0:
+			increase := float64(result.Duration-before.Duration) / float64(before.Duration) * 100
+			if increase > latencyRegression {
+				record(result, ChangeLatencyRegression, "%vms, up %.0f%% from %vms", result.Duration.Milliseconds(), increase, before.Duration.Milliseconds())
+			}
+		}
+	}
+	for _, result := range tr.Unreachable {
+		before, ok := previous[result.Path]
+		switch {
+		case !ok:
+			record(result, ChangeNewFailure, "unreachable and not in baseline")
+		case before.Passed:
+			record(result, ChangeRegression, "passed in baseline, now unreachable")
+		}
+	}
+}
+
+// EnforceBaseline returns an error if any change since the baseline is a
+// regression of an endpoint that is not quarantined. Passing results that
+// regressed in latency are marked as failed.
+func (tr *TestReport) EnforceBaseline() error {
+	quarantined := make(map[string]bool)
+	for _, result := range slices.Concat(tr.Results, tr.Unreachable) {
+		if result.Quarantined {
+			quarantined[result.Path] = true
+		}
+	}
+	regressions := 0
+	for _, change := range tr.BaselineChanges {
+		if !change.IsRegression() || quarantined[change.Path] {
+			continue
+		}
+		regressions++
+		if change.Kind != ChangeLatencyRegression {
+			continue
+		}
+		for i := range tr.Results {
+			if result := &tr.Results[i]; result.Path == change.Path && result.Passed {
+				result.Passed = false
+				result.Message = fmt.Sprintf("latency regression since baseline: %s", change.Detail)
+			}
+		}
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%d regression(s) since baseline", regressions)
+	}
+	return nil
+}
+
+// describeOutcome summarizes the outcome of a failed result that was
+// reached. Checks other than http report no HTTP status.
+func describeOutcome(result TestResult) string {
+	switch {
+	case result.Message != "":
+		return fmt.Sprintf("failing (%s)", result.Message)
+	case result.HttpStatus == 0:
+		return "failing"
+	default:
+		return fmt.Sprintf("failing with HTTP %d", result.HttpStatus)
+	}
+}
+
+// printBaselineChanges prints the changes since the baseline report.
+func (tr *TestReport) printBaselineChanges() {
+	if tr.BaselineChanges == nil {
+		return
+	}
+	fmt.Println("------------------------------")
+	fmt.Println("Changes since baseline:")
+	if len(tr.BaselineChanges) == 0 {
+		outputs.PrintColoredMessage("green", "SAME", "No changes since baseline")
+		return
+	}
+	for _, change := range tr.BaselineChanges {
+		switch change.Kind {
+		case ChangeRecovery:
+			outputs.PrintColoredMessage("green", "RECOVERED", "%s %s", change.Target, change.Detail)
+		case ChangeLatencyRegression:
+			outputs.PrintColoredMessage("yellow", "SLOWER", "%s %s", change.Target, change.Detail)
+		case ChangeNewFailure:
+			outputs.PrintColoredMessage("red", "NEW", "%s %s", change.Target, change.Detail)
+		default:
+			outputs.PrintColoredMessage("red", "REGRESSED", "%s %s", change.Target, change.Detail)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestReport_CompareBaseline(t *testing.T) {
+	baseline := &TestReport{
+		Results: []TestResult{
+			{Target: "https://example.com/stable", Path: "/stable", Duration: 100 * time.Millisecond, HttpStatus: 200, Passed: true},
+			{Target: "https://example.com/broken", Path: "/broken", Duration: 100 * time.Millisecond, HttpStatus: 200, Passed: true},
+			{Target: "https://example.com/fixed", Path: "/fixed", Duration: 100 * time.Millisecond, HttpStatus: 500, ExpectedStatus: 200},
+			{Target: "https://example.com/slower", Path: "/slower", Duration: 100 * time.Millisecond, HttpStatus: 200, Passed: true},
+			{Target: "https://example.com/gone", Path: "/gone", Duration: 100 * time.Millisecond, HttpStatus: 200, Passed: true},
+		},
+		Unreachable: []TestResult{
+			{Target: "https://example.com/back", Path: "/back"},
+		},
+	}
+	report := TestReport{
+		Results: []TestResult{
+			{Target: "https://example.com/stable", Path: "/stable", Duration: 120 * time.Millisecond, HttpStatus: 200, Passed: true},
+			{Target: "https://example.com/broken", Path: "/broken", Duration: 100 * time.Millisecond, HttpStatus: 503, ExpectedStatus: 200},
+			{Target: "https://example.com/fixed", Path: "/fixed", Duration: 100 * time.Millisecond, HttpStatus: 200, Passed: true},
+			{Target: "https://example.com/slower", Path: "/slower", Duration: 300 * time.Millisecond, HttpStatus: 200, Passed: true},
+			{Target: "https://example.com/back", Path: "/back", Duration: 100 * time.Millisecond, HttpStatus: 200, Passed: true},
+			{Target: "https://example.com/new", Path: "/new", Duration: 100 * time.Millisecond, HttpStatus: 404, ExpectedStatus: 200},
+		},
+		Unreachable: []TestResult{
+			{Target: "https://example.com/gone", Path: "/gone"},
+		},
+	}
+
+	report.CompareBaseline(baseline, DefaultLatencyRegression)
+	assert.Equal(t, []BaselineChange{
+		{Target: "https://example.com/broken", Path: "/broken", Kind: ChangeRegression, Detail: "passed in baseline, now failing with HTTP 503"},
+		{Target: "https://example.com/fixed", Path: "/fixed", Kind: ChangeRecovery, Detail: "was failing with HTTP 500 in baseline, now passes"},
+		{Target: "https://example.com/slower", Path: "/slower", Kind: ChangeLatencyRegression, Detail: "300ms, up 200% from 100ms"},
+		{Target: "https://example.com/back", Path: "/back", Kind: ChangeRecovery, Detail: "was unreachable in baseline, now passes"},
+		{Target: "https://example.com/new", Path: "/new", Kind: ChangeNewFailure, Detail: "failing and not in baseline: failing with HTTP 404"},
+		{Target: "https://example.com/gone", Path: "/gone", Kind: ChangeRegression, Detail: "passed in baseline, now unreachable"},
+	}, report.BaselineChanges)
+	assert.EqualError(t, report.EnforceBaseline(), "4 regression(s) since baseline")
+
+	slower := report.Results[3]
+	assert.False(t, slower.Passed, "latency regressions should be marked as failed")
+	assert.Equal(t, "latency regression since baseline: 300ms, up 200% from 100ms", slower.Message)
+	assert.True(t, report.Results[0].Passed)
+}
+
+func TestTestReport_CompareBaselineWithoutHTTPStatus(t *testing.T) {
+	baseline := &TestReport{
+		Results: []TestResult{
+			{Target: "tcp://cache:6379", Path: "/cache", Passed: true},
+			{Target: "dns:api.example.com?type=A", Path: "/dns"},
+		},
+		Unreachable: []TestResult{
+			{Target: "grpc://orders:50051", Path: "/orders", Message: "connection refused"},
+		},
+	}
+	report := TestReport{
+		Results: []TestResult{
+			{Target: "tcp://cache:6379", Path: "/cache", Message: "expected banner +PONG"},
+			{Target: "dns:api.example.com?type=A", Path: "/dns", Passed: true},
+			{Target: "grpc://orders:50051", Path: "/orders", Passed: true},
+		},
+	}
+
+	report.CompareBaseline(baseline, DefaultLatencyRegression)
+	assert.Equal(t, []BaselineChange{
+		{Target: "tcp://cache:6379", Path: "/cache", Kind: ChangeRegression, Detail: "passed in baseline, now failing (expected banner +PONG)"},
+		{Target: "dns:api.example.com?type=A", Path: "/dns", Kind: ChangeRecovery, Detail: "was failing in baseline, now passes"},
+		{Target: "grpc://orders:50051", Path: "/orders", Kind: ChangeRecovery, Detail: "was unreachable in baseline, now passes"},
+	}, report.BaselineChanges)
+}
+
+func TestTestReport_EnforceBaselineWithoutRegressions(t *testing.T) {
+	baseline := &TestReport{
+		Results: []TestResult{
+			{Path: "/fixed", HttpStatus: 500, ExpectedStatus: 200},
+		},
+	}
+	report := TestReport{
+		Results: []TestResult{
+			{Path: "/fixed", HttpStatus: 200, ExpectedStatus: 200, Passed: true},
+		},
+	}
+
+	report.CompareBaseline(baseline, DefaultLatencyRegression)
+	require.Len(t, report.BaselineChanges, 1)
+	assert.False(t, report.BaselineChanges[0].IsRegression())
+	assert.NoError(t, report.EnforceBaseline())
+}
+
+func TestReadReport(t *testing.T) {
+	report := TestReport{
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Results: []TestResult{
+			{Target: "https://example.com/users", Path: "/users", Duration: 42 * time.Millisecond, HttpStatus: 200, ExpectedStatus: 200, Passed: true},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+
+	decoded, err := ReadReport(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, report, *decoded)
+
+	_, err = ReadReport(bytes.NewBufferString("not json"))
+	assert.ErrorContains(t, err, "error decoding test report")
+}
@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// CertificateInfo describes the leaf certificate presented by an HTTPS target.
+type CertificateInfo struct {
+	// Subject is the distinguished name of the certificate subject.
+	Subject string `json:"subject"`
+
+	// Issuer is the distinguished name of the certificate issuer.
+	Issuer string `json:"issuer"`
+
+	// NotAfter is the expiry time of the certificate.
+	NotAfter time.Time `json:"not_after"`
+
+	// ExpiryWarning is true if the certificate expires within the warning threshold.
+	ExpiryWarning bool `json:"expiry_warning"`
+}
+
+// DaysRemaining returns the number of whole days until the certificate expires.
+func (ci *CertificateInfo) DaysRemaining() int {
+	return int(time.Until(ci.NotAfter).Hours() / 24)
+}
+
+// newCertificateInfo extracts the leaf certificate details from a TLS connection.
+func newCertificateInfo(state *tls.ConnectionState) *CertificateInfo {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	leaf := state.PeerCertificates[0]
+	return &CertificateInfo{
+		Subject:  leaf.Subject.String(),
+		Issuer:   leaf.Issuer.String(),
+		NotAfter: leaf.NotAfter,
+	}
+}
+
+// checkCertificate runs the configured certificate assertions against the TLS
+// connection state, returning a description of the first failed assertion.
+func checkCertificate(check *config.CertificateCheck, info *CertificateInfo, state *tls.ConnectionState, host string, transport http.RoundTripper) error {
+	if info == nil {
+		return fmt.Errorf("certificate check requires an HTTPS target")
+	}
+	leaf := state.PeerCertificates[0]
+
+	if time.Now().After(info.NotAfter) {
+		return fmt.Errorf("certificate expired on %s", info.NotAfter.Format(time.DateOnly))
+	}
+
+	// A verified chain means the handshake already checked the chain and
+	// hostname; otherwise verification was skipped and is done here.
+	if len(state.VerifiedChains) == 0 {
+		if err := leaf.VerifyHostname(host); err != nil {
+			return fmt.Errorf("certificate hostname mismatch: %w", err)
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range state.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		opts := x509.VerifyOptions{
+			Roots:         rootCAs(transport),
+			Intermediates: intermediates,
+		}
+		if _, err := leaf.Verify(opts); err != nil {
+			return fmt.Errorf("certificate chain is invalid: %w", err)
+		}
+	}
+
+	days := info.DaysRemaining()
+	if days < check.FailDays {
+		return fmt.Errorf("certificate expires in %d days (fail threshold %d days)", days, check.FailDays)
+	}
+	info.ExpiryWarning = days < check.WarnDays
+	return nil
+}
+
+// rootCAs returns the CA pool configured on the transport, or nil to use the
+// system pool.
+func rootCAs(transport http.RoundTripper) *x509.CertPool {
+	if t, ok := transport.(*http.Transport); ok && t.TLSClientConfig != nil {
+		return t.TLSClientConfig.RootCAs
+	}
+	return nil
+}
+
+// verificationHost returns the hostname the certificate should be valid for.
+func verificationHost(req *http.Request, transport http.RoundTripper) string {
+	if t, ok := transport.(*http.Transport); ok && t.TLSClientConfig != nil && t.TLSClientConfig.ServerName != "" {
+		return t.TLSClientConfig.ServerName
+	}
+	return req.URL.Hostname()
+}
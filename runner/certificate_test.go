@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute_CertificateCheck(t *testing.T) {
+	ca := newTestCA(t)
+	caFile := writeTestFile(t, "ca.pem", ca.CertPEM)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name            string
+		serverCert      testCertificate
+		tls             *config.TLSConfig
+		check           *config.CertificateCheck
+		expectedPassed  bool
+		expectedWarning bool
+		expectedMessage string
+	}{
+		{
+			name:           "certificate well within thresholds",
+			serverCert:     newTestServerCertificate(t, ca, time.Now().Add(90*day)),
+			tls:            &config.TLSConfig{CAFile: caFile},
+			check:          &config.CertificateCheck{WarnDays: 30, FailDays: 7},
+			expectedPassed: true,
+		},
+		{
+			name:            "certificate within warning threshold",
+			serverCert:      newTestServerCertificate(t, ca, time.Now().Add(20*day+time.Hour)),
+			tls:             &config.TLSConfig{CAFile: caFile},
+			check:           &config.CertificateCheck{WarnDays: 30, FailDays: 7},
+			expectedPassed:  true,
+			expectedWarning: true,
+		},
+		{
+			name:            "certificate within failure threshold",
+			serverCert:      newTestServerCertificate(t, ca, time.Now().Add(3*day+time.Hour)),
+			tls:             &config.TLSConfig{CAFile: caFile},
+			check:           &config.CertificateCheck{WarnDays: 30, FailDays: 7},
+			expectedMessage: "certificate expires in 3 days",
+		},
+		{
+			name:            "expired certificate",
+			serverCert:      newTestServerCertificate(t, ca, time.Now().Add(-day)),
+			tls:             &config.TLSConfig{CAFile: caFile, InsecureSkipVerify: true},
+			check:           &config.CertificateCheck{},
+			expectedMessage: "certificate expired on",
+		},
+		{
+			name: "hostname mismatch",
+			serverCert: newTestCertificate(t, &x509.Certificate{
+				Subject:     pkix.Name{CommonName: "other.example.com"},
+				DNSNames:    []string{"other.example.com"},
+				KeyUsage:    x509.KeyUsageDigitalSignature,
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			}, &ca),
+			tls:             &config.TLSConfig{CAFile: caFile, InsecureSkipVerify: true},
+			check:           &config.CertificateCheck{},
+			expectedMessage: "certificate hostname mismatch",
+		},
+		{
+			name:            "untrusted chain",
+			serverCert:      newTestServerCertificate(t, newTestCA(t), time.Time{}),
+			tls:             &config.TLSConfig{CAFile: caFile, InsecureSkipVerify: true},
+			check:           &config.CertificateCheck{},
+			expectedMessage: "certificate chain is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := newContextWithLogger(t)
+
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.TLS = &tls.Config{Certificates: []tls.Certificate{tt.serverCert.TLSCertificate(t)}}
+			server.StartTLS()
+			defer server.Close()
+
+			suite := newMockConfig(server.URL, []config.Endpoint{
+				{Path: "/", ExpectedStatus: 200, Certificate: tt.check},
+			})
+			suite.TLS = tt.tls
+
+			report, err := Execute(ctx, suite, false)
+			require.NoError(t, err)
+			require.Len(t, report.Results, 1)
+
+			result := report.Results[0]
+			require.NotNil(t, result.Certificate)
+			assert.Equal(t, tt.serverCert.Cert.Subject.String(), result.Certificate.Subject)
+			assert.Equal(t, ca.Cert.Subject.String(), result.Certificate.Issuer)
+			assert.Equal(t, tt.expectedPassed, result.Passed)
+			assert.Equal(t, tt.expectedWarning, result.Certificate.ExpiryWarning)
+			if tt.expectedMessage != "" {
+				assert.Contains(t, result.Message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestExecute_CertificateCheckPlainHTTP(t *testing.T) {
+	ctx, _ := newContextWithLogger(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	suite := newMockConfig(server.URL, []config.Endpoint{
+		{Path: "/", ExpectedStatus: 200, Certificate: &config.CertificateCheck{WarnDays: 30}},
+	})
+
+	report, err := Execute(ctx, suite, false)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.False(t, report.Results[0].Passed)
+	assert.Nil(t, report.Results[0].Certificate)
+	assert.Contains(t, report.Results[0].Message, "requires an HTTPS target")
+
+	_, err = Execute(ctx, suite, true)
+	assert.ErrorContains(t, err, "requires an HTTPS target")
+}
+
+func TestTestReport_SummarizeResults_Certificate(t *testing.T) {
+	report := TestReport{
+		Timestamp: time.Now(),
+		Results: []TestResult{
+			{
+				Target:         "https://example.com/",
+				Duration:       100 * time.Millisecond,
+				HttpStatus:     200,
+				ExpectedStatus: 200,
+				Passed:         true,
+				Certificate: &CertificateInfo{
+					Subject:       "CN=example.com",
+					Issuer:        "CN=Example CA",
+					NotAfter:      time.Now().Add(10 * 24 * time.Hour),
+					ExpiryWarning: true,
+				},
+			},
+			{
+				Target:         "https://example.com/info",
+				Duration:       100 * time.Millisecond,
+				HttpStatus:     200,
+				ExpectedStatus: 200,
+				Message:        "certificate expires in 2 days (fail threshold 7 days)",
+			},
+		},
+	}
+
+	assert.NoError(t, report.SummarizeResults())
+}
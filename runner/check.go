@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// CheckRequest is a single probe of an endpoint.
+type CheckRequest struct {
+	// Endpoint is the configured endpoint.
+	Endpoint config.Endpoint
+
+	// BaseURL is the base URL of the suite.
+	BaseURL string
+
+	// Target is the target of the endpoint as returned by the check.
+	Target string
+
+	// Client is the HTTP client of the run, for checks that speak HTTP.
+	Client *http.Client
+
+	// TLS is the TLS configuration of the suite, if any, for checks that
+	// open their own connections.
+	TLS *config.TLSConfig
+
+	// Timeout bounds the probe if neither the endpoint nor the context sets
+	// a deadline. Defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// withTimeout bounds ctx by the endpoint timeout or, if neither the endpoint
+// nor ctx sets one, by the timeout of the request.
+func (r CheckRequest) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.Endpoint.Timeout != nil {
+		return context.WithTimeout(ctx, time.Duration(*r.Endpoint.Timeout)*time.Millisecond)
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Check probes endpoints of one type. Implementations are registered with
+// RegisterCheck under the value of the endpoint type field.
+type Check interface {
+	// Validate checks the configuration of an endpoint before a run.
+	Validate(endpoint config.Endpoint) error
+
+	// Target returns the target reported for an endpoint of a suite with
+	// the given base URL.
+	Target(baseURL string, endpoint config.Endpoint) string
+
+	// Execute probes the endpoint once. An error means the target could not
+	// be reached. The runner fills in the target, path, quarantine and
+	// latency thresholds of the result.
+	Execute(ctx context.Context, req CheckRequest) (TestResult, error)
+}
+
+var (
+	checksMu sync.RWMutex
+	checks   = map[string]Check{
+		config.TypeHTTP:      httpCheck{},
+		config.TypeExec:      execCheck{},
+		config.TypeTCP:       tcpCheck{},
+		config.TypeDNS:       dnsCheck{},
+		config.TypeGRPC:      grpcCheck{},
+		config.TypeWebSocket: webSocketCheck{},
+	}
+)
+
+// RegisterCheck makes a check available for endpoints of the given type. It
+// panics if the check is nil or the type is already registered.
+func RegisterCheck(typ string, check Check) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	if check == nil {
+		panic("runner: RegisterCheck check is nil")
+	}
+	if _, ok := checks[typ]; ok {
+		panic(fmt.Sprintf("runner: RegisterCheck called twice for type '%s'", typ))
+	}
+	checks[typ] = check
+}
+
+// LookupCheck returns the check registered for the type.
+func LookupCheck(typ string) (Check, bool) {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+	check, ok := checks[typ]
+	return check, ok
+}
+
+// CheckTypes returns the registered types in sorted order.
+func CheckTypes() []string {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+	types := make([]string, 0, len(checks))
+	for typ := range checks {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// checkFor returns the check of an endpoint.
+func checkFor(endpoint config.Endpoint) (Check, error) {
+	check, ok := LookupCheck(endpoint.CheckType())
+	if !ok {
+		return nil, fmt.Errorf("unknown type '%s'", endpoint.Type)
+	}
+	return check, nil
+}
@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCheck passes endpoints whose path is "/up" and counts its probes.
+type countingCheck struct {
+	calls atomic.Int32
+}
+
+func (c *countingCheck) Validate(endpoint config.Endpoint) error {
+	if endpoint.Path == "" {
+		return errors.New("path is required")
+	}
+	return nil
+}
+
+func (c *countingCheck) Target(baseURL string, endpoint config.Endpoint) string {
+	return "counting:" + endpoint.Path
+}
+
+func (c *countingCheck) Execute(ctx context.Context, req CheckRequest) (TestResult, error) {
+	c.calls.Add(1)
+	if req.Endpoint.Path == "/down" {
+		return TestResult{}, errors.New("no route to counter")
+	}
+	return TestResult{Duration: time.Millisecond, Passed: req.Endpoint.Path == "/up"}, nil
+}
+
+func TestRegisterCheck(t *testing.T) {
+	check := &countingCheck{}
+	RegisterCheck("counting", check)
+	t.Cleanup(func() {
+		checksMu.Lock()
+		defer checksMu.Unlock()
+		delete(checks, "counting")
+	})
+
+	found, ok := LookupCheck("counting")
+	require.True(t, ok)
+	assert.Same(t, check, found)
+	assert.Contains(t, CheckTypes(), "counting")
+	assert.Contains(t, CheckTypes(), config.TypeHTTP)
+
+	assert.Panics(t, func() { RegisterCheck("counting", check) }, "types should only be registered once")
+	assert.Panics(t, func() { RegisterCheck("nothing", nil) })
+
+	maxLatency := 50
+	suite := &config.TestSuite{
+		Endpoints: []config.Endpoint{
+			{Type: "counting", Path: "/up", Samples: 3, MaxLatency: &maxLatency, Quarantine: true},
+			{Type: "counting", Path: "/sideways"},
+			{Type: "counting", Path: "/down"},
+		},
+	}
+	report, err := Run(context.Background(), suite, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), check.calls.Load(), "samples should run through the worker pool")
+
+	require.Len(t, report.Results, 2)
+	up := report.Results[0]
+	assert.True(t, up.Passed)
+	assert.Equal(t, "counting:/up", up.Target)
+	assert.Equal(t, "/up", up.Path)
+	assert.True(t, up.Quarantined, "the runner should fill in the common fields")
+	require.NotNil(t, up.MaxLatency)
+	assert.Equal(t, 50*time.Millisecond, *up.MaxLatency)
+	require.NotNil(t, up.Stats)
+	assert.Equal(t, 3, up.Stats.Samples)
+	assert.False(t, report.Results[1].Passed)
+
+	require.Len(t, report.Unreachable, 1)
+	assert.Equal(t, "counting:/down", report.Unreachable[0].Target)
+	assert.Contains(t, report.Unreachable[0].Message, "no route to counter")
+
+	_, err = Run(context.Background(), &config.TestSuite{
+		Endpoints: []config.Endpoint{{Type: "counting"}},
+	}, Options{})
+	assert.EqualError(t, err, "invalid endpoint #1: path is required")
+}
@@ -0,0 +1,198 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// DNS record types supported by dns checks.
+const (
+	recordA     string = "A"
+	recordAAAA  string = "AAAA"
+	recordCNAME string = "CNAME"
+	recordTXT   string = "TXT"
+	recordSRV   string = "SRV"
+)
+
+// dnsCheck resolves a name and asserts on the answers.
+type dnsCheck struct{}
+
+func (dnsCheck) Validate(endpoint config.Endpoint) error {
+	conf := endpoint.DNS
+	if conf == nil || conf.Name == "" {
+		return errors.New("dns.name is required")
+	}
+	switch recordType(conf) {
+	case recordA, recordAAAA, recordCNAME, recordTXT, recordSRV:
+	default:
+		return fmt.Errorf("unsupported dns.record-type '%s'", conf.RecordType)
+	}
+	if conf.MinRecords < 0 {
+		return fmt.Errorf("dns.min-records must not be negative, got %d", conf.MinRecords)
+	}
+	return nil
+}
+
+func (dnsCheck) Target(baseURL string, endpoint config.Endpoint) string {
+	if endpoint.DNS == nil {
+		return "dns:"
+	}
+	conf := endpoint.DNS
+	if conf.Server == "" {
+		return fmt.Sprintf("dns:%s?type=%s", conf.Name, recordType(conf))
+	}
+	return fmt.Sprintf("dns://%s/%s?type=%s", conf.Server, conf.Name, recordType(conf))
+}
+
+// Execute resolves the name of the endpoint. A name that does not exist fails
+// the check; a server that cannot be queried leaves the endpoint unreachable.
+func (dnsCheck) Execute(ctx context.Context, req CheckRequest) (TestResult, error) {
+	conf := req.Endpoint.DNS
+	if req.Endpoint.Timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*req.Endpoint.Timeout)*time.Millisecond)
+		defer cancel()
+	}
+	resolver := newResolver(conf.Server)
+
+	start := time.Now()
+	answers, err := lookup(ctx, resolver, recordType(conf), conf.Name)
+	var canonical string
+	if err == nil && conf.CNAME != "" {
+		canonical, err = resolver.LookupCNAME(ctx, conf.Name)
+	}
+	duration := time.Since(start)
+
+	result := TestResult{
+		Duration: duration,
+		Passed:   true,
+		Body:     []byte(strings.Join(answers, "\n")),
+	}
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		result.Passed = false
+		result.Message = fmt.Sprintf("%s has no %s records", conf.Name, recordType(conf))
+		return result, nil
+	case err != nil:
+		return TestResult{}, err
+	}
+
+	if message := checkAnswers(conf, answers, canonical); message != "" {
+		result.Passed = false
+		result.Message = message
+	}
+	return result, nil
+}
+
+// recordType returns the configured record type, defaulting to A.
+func recordType(conf *config.DNSCheck) string {
+	if conf.RecordType == "" {
+		return recordA
+	}
+	return strings.ToUpper(conf.RecordType)
+}
+
+// newResolver returns the system resolver, or a resolver querying the server
+// if one is set.
+func newResolver(server string) *net.Resolver {
+	if server == "" {
+		return net.DefaultResolver
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// lookup resolves the records of the type, formatting each answer as text.
+func lookup(ctx context.Context, resolver *net.Resolver, typ string, name string) ([]string, error) {
+	switch typ {
+	case recordA, recordAAAA:
+		network := "ip4"
+		if typ == recordAAAA {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, name)
+		answers := make([]string, len(ips))
+		for i, ip := range ips {
+			answers[i] = ip.String()
+		}
+		return answers, err
+	case recordCNAME:
+		canonical, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return []string{trimDot(canonical)}, nil
+	case recordTXT:
+		return resolver.LookupTXT(ctx, name)
+	default:
+		_, records, err := resolver.LookupSRV(ctx, "", "", name)
+		answers := make([]string, len(records))
+		for i, record := range records {
+			answers[i] = net.JoinHostPort(trimDot(record.Target), strconv.Itoa(int(record.Port)))
+		}
+		return answers, err
+	}
+}
+
+// checkAnswers describes why the answers do not meet the assertions of the
+// check, returning an empty string if they do.
+func checkAnswers(conf *config.DNSCheck, answers []string, canonical string) string {
+	if len(answers) < conf.MinRecords {
+		return fmt.Sprintf("expected at least %d %s records but got %d", conf.MinRecords, recordType(conf), len(answers))
+	}
+	typ := recordType(conf)
+	normalized := make([]string, len(answers))
+	for i, answer := range answers {
+		normalized[i] = normalizeAnswer(typ, answer)
+	}
+	var missing []string
+	for _, expected := range conf.Expect {
+		if !slices.Contains(normalized, normalizeAnswer(typ, expected)) {
+			missing = append(missing, expected)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("missing %s answers %s, got [%s]", typ, strings.Join(missing, ", "), strings.Join(answers, ", "))
+	}
+	if conf.CNAME != "" && normalizeAnswer(recordCNAME, canonical) != normalizeAnswer(recordCNAME, conf.CNAME) {
+		return fmt.Sprintf("expected CNAME %s but got %s", trimDot(conf.CNAME), trimDot(canonical))
+	}
+	return ""
+}
+
+// normalizeAnswer makes answers of the type comparable regardless of how
+// addresses are written, and names regardless of case and trailing dot.
+func normalizeAnswer(typ string, answer string) string {
+	switch typ {
+	case recordTXT:
+		return answer
+	case recordA, recordAAAA:
+		if ip := net.ParseIP(answer); ip != nil {
+			return ip.String()
+		}
+		return answer
+	default:
+		return strings.ToLower(trimDot(answer))
+	}
+}
+
+func trimDot(name string) string {
+	return strings.TrimSuffix(name, ".")
+}
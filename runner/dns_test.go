@@ -0,0 +1,246 @@
+package runner
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// DNS wire format record types used by the stand-in server.
+const (
+	dnsTypeA     uint16 = 1
+	dnsTypeCNAME uint16 = 5
+	dnsTypeTXT   uint16 = 16
+	dnsTypeAAAA  uint16 = 28
+	dnsTypeSRV   uint16 = 33
+)
+
+// dnsRecord is a resource record served by the stand-in server.
+type dnsRecord struct {
+	typ   uint16
+	rdata []byte
+}
+
+// encodeName encodes a domain name in DNS wire format without compression.
+func encodeName(name string) []byte {
+	var encoded []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		encoded = append(encoded, byte(len(label)))
+		encoded = append(encoded, label...)
+	}
+	return append(encoded, 0)
+}
+
+func aRecord(ip string) dnsRecord {
+	return dnsRecord{typ: dnsTypeA, rdata: net.ParseIP(ip).To4()}
+}
+
+func aaaaRecord(ip string) dnsRecord {
+	return dnsRecord{typ: dnsTypeAAAA, rdata: net.ParseIP(ip).To16()}
+}
+
+func cnameRecord(target string) dnsRecord {
+	return dnsRecord{typ: dnsTypeCNAME, rdata: encodeName(target)}
+}
+
+func txtRecord(text string) dnsRecord {
+	return dnsRecord{typ: dnsTypeTXT, rdata: append([]byte{byte(len(text))}, text...)}
+}
+
+func srvRecord(target string, port uint16) dnsRecord {
+	rdata := binary.BigEndian.AppendUint16(nil, 10)
+	rdata = binary.BigEndian.AppendUint16(rdata, 5)
+	rdata = binary.BigEndian.AppendUint16(rdata, port)
+	return dnsRecord{typ: dnsTypeSRV, rdata: append(rdata, encodeName(target)...)}
+}
+
+// newDNSStandIn serves the zone over UDP on a local port, following CNAME
+// records and answering NXDOMAIN for unknown names.
+func newDNSStandIn(t *testing.T, zone map[string][]dnsRecord) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if response := answerQuery(zone, buf[:n]); response != nil {
+				_, _ = conn.WriteTo(response, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// answerQuery builds the response to a single-question query.
+func answerQuery(zone map[string][]dnsRecord, query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+	end := 12
+	var labels []string
+	for end < len(query) && query[end] != 0 {
+		length := int(query[end])
+		labels = append(labels, string(query[end+1:end+1+length]))
+		end += length + 1
+	}
+	question := query[12 : end+5]
+	qtype := binary.BigEndian.Uint16(query[end+1 : end+3])
+	name := strings.ToLower(strings.Join(labels, "."))
+
+	var answers []byte
+	count := 0
+	owner := name
+	_, found := zone[owner]
+	for owner != "" {
+		next := ""
+		for _, record := range zone[owner] {
+			if record.typ != qtype && record.typ != dnsTypeCNAME {
+				continue
+			}
+			answers = append(answers, encodeName(owner)...)
+			answers = binary.BigEndian.AppendUint16(answers, record.typ)
+			answers = binary.BigEndian.AppendUint16(answers, 1)
+			answers = binary.BigEndian.AppendUint32(answers, 60)
+			answers = binary.BigEndian.AppendUint16(answers, uint16(len(record.rdata)))
+			answers = append(answers, record.rdata...)
+			count++
+			if record.typ == dnsTypeCNAME && qtype != dnsTypeCNAME {
+				next = decodeName(record.rdata)
+			}
+		}
+		owner = next
+	}
+
+	flags := uint16(0x8580) // response, authoritative, recursion desired and available
+	if !found {
+		flags |= 3 // NXDOMAIN
+	}
+	response := append([]byte{}, query[0:2]...)
+	response = binary.BigEndian.AppendUint16(response, flags)
+	response = binary.BigEndian.AppendUint16(response, 1)
+	response = binary.BigEndian.AppendUint16(response, uint16(count))
+	response = binary.BigEndian.AppendUint32(response, 0)
+	response = append(response, question...)
+	return append(response, answers...)
+}
+
+func decodeName(encoded []byte) string {
+	var labels []string
+	for i := 0; i < len(encoded) && encoded[i] != 0; i += int(encoded[i]) + 1 {
+		labels = append(labels, string(encoded[i+1:i+1+int(encoded[i])]))
+	}
+	return strings.Join(labels, ".")
+}
+
+func TestRun_DNSCheck(t *testing.T) {
+	server := newDNSStandIn(t, map[string][]dnsRecord{
+		"api.example.test":          {cnameRecord("lb.example.test")},
+		"lb.example.test":           {aRecord("192.0.2.10"), aRecord("192.0.2.11"), aaaaRecord("2001:db8::10")},
+		"example.test":              {txtRecord("v=spf1 -all")},
+		"_https._tcp.example.test":  {srvRecord("web.example.test", 8443)},
+		"single.example.test":       {aRecord("192.0.2.20")},
+		"other-target.example.test": {cnameRecord("elsewhere.example.test")},
+		"elsewhere.example.test":    {aRecord("192.0.2.30")},
+	})
+	timeout := 2000
+	dnsEndpoint := func(path string, check config.DNSCheck) config.Endpoint {
+		check.Server = server
+		return config.Endpoint{Type: config.TypeDNS, Path: path, Timeout: &timeout, DNS: &check}
+	}
+
+	tests := []struct {
+		name     string
+		check    config.DNSCheck
+		passed   bool
+		message  string
+		expected []string
+	}{
+		{
+			name:     "A records through CNAME",
+			check:    config.DNSCheck{Name: "api.example.test", Expect: []string{"192.0.2.11"}, MinRecords: 2, CNAME: "LB.example.test."},
+			passed:   true,
+			expected: []string{"192.0.2.10", "192.0.2.11"},
+		},
+		{
+			name:     "AAAA records",
+			check:    config.DNSCheck{Name: "lb.example.test", RecordType: "aaaa", Expect: []string{"2001:db8:0::10"}},
+			passed:   true,
+			expected: []string{"2001:db8::10"},
+		},
+		{
+			name:     "CNAME record",
+			check:    config.DNSCheck{Name: "api.example.test", RecordType: "CNAME", Expect: []string{"lb.example.test"}},
+			passed:   true,
+			expected: []string{"lb.example.test"},
+		},
+		{
+			name:     "TXT record",
+			check:    config.DNSCheck{Name: "example.test", RecordType: "TXT", Expect: []string{"v=spf1 -all"}},
+			passed:   true,
+			expected: []string{"v=spf1 -all"},
+		},
+		{
+			name:     "SRV record",
+			check:    config.DNSCheck{Name: "_https._tcp.example.test", RecordType: "SRV", Expect: []string{"web.example.test:8443"}},
+			passed:   true,
+			expected: []string{"web.example.test:8443"},
+		},
+		{
+			name:    "missing answer",
+			check:   config.DNSCheck{Name: "single.example.test", Expect: []string{"192.0.2.99"}},
+			message: "missing A answers 192.0.2.99, got [192.0.2.20]",
+		},
+		{
+			name:    "too few records",
+			check:   config.DNSCheck{Name: "single.example.test", MinRecords: 2},
+			message: "expected at least 2 A records but got 1",
+		},
+		{
+			name:    "wrong CNAME target",
+			check:   config.DNSCheck{Name: "other-target.example.test", CNAME: "lb.example.test"},
+			message: "expected CNAME lb.example.test but got elsewhere.example.test",
+		},
+		{
+			name:    "unknown name",
+			check:   config.DNSCheck{Name: "missing.example.test"},
+			message: "missing.example.test has no A records",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := &config.TestSuite{Endpoints: []config.Endpoint{dnsEndpoint("/dns", tc.check)}}
+			report, err := Run(context.Background(), suite, Options{})
+			require.NoError(t, err)
+			require.Len(t, report.Results, 1, "the server should be reachable")
+			result := report.Results[0]
+			assert.Equal(t, tc.passed, result.Passed, result.Message)
+			assert.Equal(t, tc.message, result.Message)
+			assert.Positive(t, result.Duration, "resolution latency should be reported")
+			if tc.expected != nil {
+				assert.ElementsMatch(t, tc.expected, strings.Split(string(result.Body), "\n"))
+			}
+		})
+	}
+}
+
+func TestDNSCheck_TargetAndValidate(t *testing.T) {
+	check := dnsCheck{}
+	assert.Equal(t, "dns:example.com?type=A", check.Target("", config.Endpoint{DNS: &config.DNSCheck{Name: "example.com"}}))
+	assert.Equal(t, "dns://1.1.1.1/example.com?type=TXT", check.Target("", config.Endpoint{DNS: &config.DNSCheck{Name: "example.com", RecordType: "txt", Server: "1.1.1.1"}}))
+
+	assert.EqualError(t, check.Validate(config.Endpoint{}), "dns.name is required")
+	assert.EqualError(t, check.Validate(config.Endpoint{DNS: &config.DNSCheck{Name: "example.com", RecordType: "MX"}}), "unsupported dns.record-type 'MX'")
+	assert.NoError(t, check.Validate(config.Endpoint{DNS: &config.DNSCheck{Name: "example.com", RecordType: "srv"}}))
+}
@@ -0,0 +1,147 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// maxPluginStderr is the maximum number of stderr bytes of a plugin included
+// in an error.
+const maxPluginStderr int = 512
+
+// ExecRequest is the JSON document an exec plugin receives on stdin.
+type ExecRequest struct {
+	// Name is the name of the endpoint.
+	Name string `json:"name,omitempty"`
+
+	// Path identifies the endpoint.
+	Path string `json:"path"`
+
+	// URL is the base URL of the suite.
+	URL string `json:"url,omitempty"`
+
+	// Tags are the tags of the endpoint.
+	Tags []string `json:"tags,omitempty"`
+
+	// TimeoutMs is the timeout of the check in milliseconds, if any.
+	TimeoutMs *int `json:"timeout_ms,omitempty"`
+
+	// Config is the plugin configuration of the endpoint.
+	Config map[string]any `json:"config,omitempty"`
+}
+
+// ExecResponse is the JSON document an exec plugin writes to stdout.
+type ExecResponse struct {
+	// Passed is true if the check passed.
+	Passed bool `json:"passed"`
+
+	// DurationMs is the duration of the check in milliseconds. Defaults to
+	// the run time of the plugin.
+	DurationMs *float64 `json:"duration_ms,omitempty"`
+
+	// Message describes the outcome of the check.
+	Message string `json:"message,omitempty"`
+
+	// Metrics are named measurements taken by the check.
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+}
+
+// execCheck runs an external plugin executable to probe endpoints.
+type execCheck struct{}
+
+func (execCheck) Validate(endpoint config.Endpoint) error {
+	if endpoint.Exec == nil || endpoint.Exec.Command == "" {
+		return errors.New("exec.command is required")
+	}
+	return nil
+}
+
+func (execCheck) Target(baseURL string, endpoint config.Endpoint) string {
+	return "exec:" + endpoint.Path
+}
+
+// Execute runs the plugin of the endpoint. A plugin that cannot be run, times
+// out or does not write a valid response leaves the endpoint unreachable; a
+// plugin exiting with an error after writing a response reports that
+// response.
+func (execCheck) Execute(ctx context.Context, req CheckRequest) (TestResult, error) {
+	plugin := req.Endpoint.Exec
+	if req.Endpoint.Timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*req.Endpoint.Timeout)*time.Millisecond)
+		defer cancel()
+	}
+
+	request, err := json.Marshal(ExecRequest{
+		Name:      req.Endpoint.Name,
+		Path:      req.Endpoint.Path,
+		URL:       req.BaseURL,
+		Tags:      req.Endpoint.Tags,
+		TimeoutMs: req.Endpoint.Timeout,
+		Config:    plugin.Config,
+	})
+	if err != nil {
+		return TestResult{}, fmt.Errorf("error encoding plugin request: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, plugin.Command, plugin.Args...)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+	for key, value := range plugin.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	runErr := cmd.Run()
+	duration := time.Since(start)
+	if ctx.Err() != nil {
+		return TestResult{}, fmt.Errorf("plugin %s: %w", plugin.Command, ctx.Err())
+	}
+
+	var response ExecResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		if runErr != nil {
+			return TestResult{}, fmt.Errorf("plugin %s failed: %w%s", plugin.Command, runErr, stderrNote(stderr.Bytes()))
+		}
+		return TestResult{}, fmt.Errorf("plugin %s wrote an invalid response: %w", plugin.Command, err)
+	}
+
+	result := TestResult{
+		Duration: duration,
+		Passed:   response.Passed,
+		Message:  response.Message,
+		Metrics:  response.Metrics,
+	}
+	if response.DurationMs != nil {
+		result.Duration = time.Duration(*response.DurationMs * float64(time.Millisecond))
+	}
+	if !result.Passed && result.Message == "" {
+		result.Message = "check failed"
+	}
+	return result, nil
+}
+
+// stderrNote formats the start of the stderr output of a plugin for an error.
+func stderrNote(stderr []byte) string {
+	trimmed := strings.TrimSpace(string(stderr))
+	if trimmed == "" {
+		return ""
+	}
+	if len(trimmed) > maxPluginStderr {
+		trimmed = trimmed[:maxPluginStderr] + "..."
+	}
+	return ": " + trimmed
+}
@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pluginModeEnv makes the test binary act as an exec plugin.
+const pluginModeEnv = "SMOKESWEEP_TEST_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(pluginModeEnv); mode != "" {
+		os.Exit(runTestPlugin(mode))
+	}
+	os.Exit(m.Run())
+}
+
+// runTestPlugin implements the exec plugin protocol for the given mode.
+func runTestPlugin(mode string) int {
+	var request ExecRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	switch mode {
+	case "echo":
+		topic, _ := request.Config["topic"].(string)
+		_ = json.NewEncoder(os.Stdout).Encode(ExecResponse{
+			Passed:  topic == "orders",
+			Message: fmt.Sprintf("%s %s %s", request.Path, request.URL, os.Getenv("EXTRA")),
+			Metrics: map[string]float64{"lag": 3},
+		})
+		return 0
+	case "fail":
+		duration := 12.5
+		_ = json.NewEncoder(os.Stdout).Encode(ExecResponse{Passed: false, DurationMs: &duration})
+		return 1
+	case "crash":
+		fmt.Fprintln(os.Stderr, "broker unavailable")
+		return 3
+	case "garbage":
+		fmt.Println("not json")
+		return 0
+	case "sleep":
+		time.Sleep(5 * time.Second)
+		return 0
+	}
+	return 2
+}
+
+func pluginEndpoint(path string, mode string) config.Endpoint {
+	return config.Endpoint{
+		Type: config.TypeExec,
+		Path: path,
+		Exec: &config.ExecCheck{
+			Command: os.Args[0],
+			Env:     map[string]string{pluginModeEnv: mode, "EXTRA": "env"},
+			Config:  map[string]any{"topic": "orders"},
+		},
+	}
+}
+
+func TestRun_ExecPlugin(t *testing.T) {
+	timeout := 200
+	sleeping := pluginEndpoint("/sleep", "sleep")
+	sleeping.Timeout = &timeout
+	suite := &config.TestSuite{
+		URL: "https://example.com",
+		Endpoints: []config.Endpoint{
+			pluginEndpoint("/kafka/orders", "echo"),
+			pluginEndpoint("/fail", "fail"),
+			pluginEndpoint("/crash", "crash"),
+			pluginEndpoint("/garbage", "garbage"),
+			sleeping,
+		},
+	}
+
+	started := time.Now()
+	report, err := Run(context.Background(), suite, Options{})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(started), 4*time.Second, "the endpoint timeout should stop the plugin")
+
+	require.Len(t, report.Results, 2)
+	passed := report.Results[0]
+	assert.True(t, passed.Passed)
+	assert.Equal(t, "exec:/kafka/orders", passed.Target)
+	assert.Equal(t, "/kafka/orders https://example.com env", passed.Message, "the plugin should receive the endpoint on stdin")
+	assert.Equal(t, map[string]float64{"lag": 3}, passed.Metrics)
+	assert.Positive(t, passed.Duration)
+
+	failed := report.Results[1]
+	assert.False(t, failed.Passed)
+	assert.Equal(t, "check failed", failed.Message)
+	assert.Equal(t, 12500*time.Microsecond, failed.Duration, "the reported duration should be used")
+
+	require.Len(t, report.Unreachable, 3)
+	assert.Equal(t, "/crash", report.Unreachable[0].Path)
+	assert.Contains(t, report.Unreachable[0].Message, "broker unavailable")
+	assert.Contains(t, report.Unreachable[1].Message, "invalid response")
+	assert.Contains(t, report.Unreachable[2].Message, "deadline exceeded")
+}
+
+func TestRun_InvalidEndpointType(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint config.Endpoint
+		expected string
+	}{
+		{
+			name:     "unknown type",
+			endpoint: config.Endpoint{Type: "carrier-pigeon", Path: "/coop"},
+			expected: "invalid endpoint /coop: unknown type 'carrier-pigeon'",
+		},
+		{
+			name:     "exec without command",
+			endpoint: config.Endpoint{Type: config.TypeExec, Path: "/plugin"},
+			expected: "invalid endpoint /plugin: exec.command is required",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := &config.TestSuite{Endpoints: []config.Endpoint{tc.endpoint}}
+			_, err := Run(context.Background(), suite, Options{})
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
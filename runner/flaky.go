@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"math"
+	"slices"
+)
+
+// DefaultFlakyThreshold is the flakiness score at or above which an endpoint
+// is reported as flaky.
+const DefaultFlakyThreshold float64 = 0.3
+
+// MinFlakyOutcomes is the number of outcomes of an endpoint needed before its
+// history is scored for flakiness, so that a single failure of a new
+// endpoint is not mistaken for flakiness.
+const MinFlakyOutcomes int = 5
+
+// TransitionRate returns the fraction of consecutive outcomes that differ,
+// from 0 when the outcome never changes to 1 when it changes every time.
+func TransitionRate(outcomes []bool) float64 {
+	if len(outcomes) < 2 {
+		return 0
+	}
+	transitions := 0
+	for i := 1; i < len(outcomes); i++ {
+		if outcomes[i] != outcomes[i-1] {
+			transitions++
+		}
+	}
+	return float64(transitions) / float64(len(outcomes)-1)
+}
+
+// HistoryFlakiness scores the flakiness of an endpoint from its outcomes,
+// oldest first. Histories shorter than MinFlakyOutcomes score 0.
+func HistoryFlakiness(outcomes []bool) float64 {
+	if len(outcomes) < MinFlakyOutcomes {
+		return 0
+	}
+	return TransitionRate(outcomes)
+}
+
+// IsFlaky reports whether a flakiness score reaches the threshold. A score of
+// 0 is never flaky.
+func IsFlaky(score float64, threshold float64) bool {
+	return score > 0 && score >= threshold
+}
+
+// mixedOutcomes scores how evenly the samples of an endpoint are split
+// between passes and failures, from 0 when they all agree to 1 for an even
+// split.
+func mixedOutcomes(stats *LatencyStats) float64 {
+	if stats == nil || stats.Samples < 2 {
+		return 0
+	}
+	return 1 - math.Abs(2*stats.SuccessRatio()-1)
+}
+
+// DetectFlaky scores the flakiness of every result from its samples and from
+// the previous outcomes of its path, oldest first, marking results whose
+// score reaches the threshold as flaky.
+func (tr *TestReport) DetectFlaky(previous map[string][]bool, threshold float64) {
+	for i := range tr.Results {
+		result := &tr.Results[i]
+		score := mixedOutcomes(result.Stats)
+		if outcomes := previous[result.Path]; len(outcomes) > 0 {
+			score = max(score, HistoryFlakiness(append(slices.Clone(outcomes), result.Passed)))
+		}
+		result.Flakiness = score
+		result.Flaky = IsFlaky(score, threshold)
+	}
+}
+
+// QuarantineFlaky quarantines every result marked as flaky.
+func (tr *TestReport) QuarantineFlaky() {
+	for i := range tr.Results {
+		if tr.Results[i].Flaky {
+			tr.Results[i].Quarantined = true
+		}
+	}
+}
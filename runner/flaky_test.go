@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+func TestTransitionRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		outcomes []bool
+		expected float64
+	}{
+		{name: "no outcomes", outcomes: nil, expected: 0},
+		{name: "single outcome", outcomes: []bool{false}, expected: 0},
+		{name: "always passing", outcomes: []bool{true, true, true}, expected: 0},
+		{name: "single transition", outcomes: []bool{true, true, false, false, false}, expected: 0.25},
+		{name: "always flipping", outcomes: []bool{true, false, true, false}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, TransitionRate(tt.outcomes), 1e-9)
+		})
+	}
+}
+
+func TestHistoryFlakiness(t *testing.T) {
+	assert.Zero(t, HistoryFlakiness([]bool{true, true, false}), "short histories should not be scored")
+	assert.InDelta(t, 0.25, HistoryFlakiness([]bool{true, true, true, true, false}), 1e-9)
+
+	assert.False(t, IsFlaky(0, 0), "a consistent endpoint should never be flaky")
+	assert.True(t, IsFlaky(0.3, DefaultFlakyThreshold))
+	assert.False(t, IsFlaky(0.2, DefaultFlakyThreshold))
+}
+
+func TestTestReport_DetectFlaky(t *testing.T) {
+	report := TestReport{
+		Results: []TestResult{
+			{Path: "/stable", Passed: true, Stats: &LatencyStats{Samples: 10, Successes: 10}},
+			{Path: "/mixed", Passed: false, Stats: &LatencyStats{Samples: 4, Successes: 2}},
+			{Path: "/mostly", Passed: false, Stats: &LatencyStats{Samples: 10, Successes: 9}},
+			{Path: "/flipping", Passed: true},
+			{Path: "/new", Passed: false},
+			{Path: "/down", Passed: false},
+		},
+	}
+	previous := map[string][]bool{
+		"/stable":   {true, true, true},
+		"/flipping": {true, false, true, false},
+		"/new":      {true, true},
+		"/down":     {false, false, false},
+	}
+
+	report.DetectFlaky(previous, DefaultFlakyThreshold)
+
+	expected := map[string]struct {
+		flakiness float64
+		flaky     bool
+	}{
+		"/stable":   {0, false},
+		"/mixed":    {1, true},
+		"/mostly":   {0.2, false},
+		"/flipping": {1, true},
+		"/new":      {0, false},
+		"/down":     {0, false},
+	}
+	for _, result := range report.Results {
+		assert.InDelta(t, expected[result.Path].flakiness, result.Flakiness, 1e-9, result.Path)
+		assert.Equal(t, expected[result.Path].flaky, result.Flaky, result.Path)
+	}
+
+	report.QuarantineFlaky()
+	for _, result := range report.Results {
+		assert.Equal(t, result.Flaky, result.Quarantined, result.Path)
+	}
+}
+
+func TestQuarantinedEndpointsDoNotFailRun(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/flaky" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	suite := &config.TestSuite{
+		URL: server.URL,
+		Endpoints: []config.Endpoint{
+			{Path: "/flaky", ExpectedStatus: 200, Quarantine: true},
+			{Path: "/stable", ExpectedStatus: 200},
+		},
+	}
+
+	ctx, _ := newContextWithLogger(t)
+	report, err := Execute(ctx, suite, true)
+	require.NoError(t, err, "a quarantined failure should not stop a fail-fast run")
+	require.Len(t, report.Results, 2)
+	assert.False(t, report.Results[0].Passed)
+	assert.True(t, report.Results[0].Quarantined)
+	assert.True(t, report.Results[1].Passed)
+	assert.Equal(t, int32(2), requests.Load())
+}
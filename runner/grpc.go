@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// grpcCheck calls the standard gRPC health checking service and asserts on
+// the serving status.
+type grpcCheck struct{}
+
+func (grpcCheck) Validate(endpoint config.Endpoint) error {
+	conf := endpoint.GRPC
+	if conf == nil || conf.Address == "" {
+		return errors.New("grpc.address is required")
+	}
+	if _, _, err := net.SplitHostPort(conf.Address); err != nil {
+		return fmt.Errorf("invalid grpc.address: %w", err)
+	}
+	if conf.Plaintext && conf.TLS != nil {
+		return errors.New("grpc.plaintext and grpc.tls are mutually exclusive")
+	}
+	if _, ok := healthpb.HealthCheckResponse_ServingStatus_value[expectedServingStatus(conf)]; !ok {
+		return fmt.Errorf("unknown grpc.expected-status '%s'", conf.ExpectedStatus)
+	}
+	return nil
+}
+
+func (grpcCheck) Target(baseURL string, endpoint config.Endpoint) string {
+	if endpoint.GRPC == nil {
+		return "grpc://"
+	}
+	return fmt.Sprintf("grpc://%s/%s", endpoint.GRPC.Address, endpoint.GRPC.Service)
+}
+
+// Execute calls Health/Check for the service of the endpoint. A server that
+// cannot be reached leaves the endpoint unreachable; any other error or an
+// unexpected serving status fails it.
+func (grpcCheck) Execute(ctx context.Context, req CheckRequest) (TestResult, error) {
+	conf := req.Endpoint.GRPC
+	ctx, cancel := req.withTimeout(ctx)
+	defer cancel()
+
+	creds, err := grpcCredentials(conf, req.TLS)
+	if err != nil {
+		return TestResult{}, err
+	}
+	conn, err := grpc.NewClient(conf.Address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return TestResult{}, fmt.Errorf("error creating gRPC client: %w", err)
+	}
+	defer conn.Close()
+
+	if len(conf.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(conf.Metadata))
+	}
+
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: conf.Service})
+	duration := time.Since(start)
+
+	result := TestResult{Duration: duration}
+	switch code := status.Code(err); code {
+	case codes.OK:
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return TestResult{}, err
+	default:
+		result.Message = fmt.Sprintf("health check failed with %s: %s", code, status.Convert(err).Message())
+		return result, nil
+	}
+
+	expected := expectedServingStatus(conf)
+	result.Body = []byte(resp.GetStatus().String())
+	result.Passed = resp.GetStatus().String() == expected
+	if !result.Passed {
+		result.Message = fmt.Sprintf("expected %s but got %s", expected, resp.GetStatus())
+	}
+	return result, nil
+}
+
+// expectedServingStatus returns the configured serving status, defaulting to
+// SERVING.
+func expectedServingStatus(conf *config.GRPCCheck) string {
+	if conf.ExpectedStatus == "" {
+		return healthpb.HealthCheckResponse_SERVING.String()
+	}
+	return conf.ExpectedStatus
+}
+
+// grpcCredentials returns the transport credentials of the connection.
+// Endpoints without TLS settings of their own use those of the suite.
+func grpcCredentials(conf *config.GRPCCheck, suiteTLS *config.TLSConfig) (credentials.TransportCredentials, error) {
+	if conf.Plaintext {
+		return insecure.NewCredentials(), nil
+	}
+	tlsConf := conf.TLS
+	if tlsConf == nil {
+		tlsConf = suiteTLS
+	}
+	if tlsConf == nil {
+		return credentials.NewTLS(&tls.Config{}), nil
+	}
+	tlsConfig, err := buildTLSConfig(tlsConf)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring TLS: %w", err)
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
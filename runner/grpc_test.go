@@ -0,0 +1,174 @@
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGRPCHealthStandIn serves the standard health service on a local port,
+// rejecting calls to the "private" service without an authorization header
+// and never answering calls to the "hanging" service.
+func newGRPCHealthStandIn(t *testing.T, opts ...grpc.ServerOption) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	requireAuth := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if check, ok := req.(*healthpb.HealthCheckRequest); ok && check.Service == "hanging" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		if check, ok := req.(*healthpb.HealthCheckRequest); ok && check.Service == "private" {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if len(md.Get("authorization")) == 0 || md.Get("authorization")[0] != "Bearer secret" {
+				return nil, status.Error(codes.Unauthenticated, "missing token")
+			}
+		}
+		return handler(ctx, req)
+	}
+	server := grpc.NewServer(append(opts, grpc.UnaryInterceptor(requireAuth))...)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("payments", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus("private", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestRun_GRPCCheck(t *testing.T) {
+	address := newGRPCHealthStandIn(t)
+	timeout := 2000
+	grpcEndpoint := func(path string, check config.GRPCCheck) config.Endpoint {
+		check.Address = address
+		check.Plaintext = true
+		return config.Endpoint{Type: config.TypeGRPC, Path: path, Timeout: &timeout, GRPC: &check}
+	}
+
+	tests := []struct {
+		name    string
+		check   config.GRPCCheck
+		passed  bool
+		message string
+	}{
+		{name: "server health", check: config.GRPCCheck{}, passed: true},
+		{name: "serving service", check: config.GRPCCheck{Service: "orders"}, passed: true},
+		{name: "not serving service", check: config.GRPCCheck{Service: "payments"}, message: "expected SERVING but got NOT_SERVING"},
+		{name: "expected not serving", check: config.GRPCCheck{Service: "payments", ExpectedStatus: "NOT_SERVING"}, passed: true},
+		{name: "unknown service", check: config.GRPCCheck{Service: "inventory"}, message: "health check failed with NotFound: unknown service"},
+		{name: "metadata", check: config.GRPCCheck{Service: "private", Metadata: map[string]string{"authorization": "Bearer secret"}}, passed: true},
+		{name: "missing metadata", check: config.GRPCCheck{Service: "private"}, message: "health check failed with Unauthenticated: missing token"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := &config.TestSuite{Endpoints: []config.Endpoint{grpcEndpoint("/grpc", tc.check)}}
+			report, err := Run(context.Background(), suite, Options{})
+			require.NoError(t, err)
+			require.Len(t, report.Results, 1, "the server should be reachable")
+			result := report.Results[0]
+			assert.Equal(t, tc.passed, result.Passed, result.Message)
+			assert.Equal(t, tc.message, result.Message)
+			assert.Equal(t, "grpc://"+address+"/"+tc.check.Service, result.Target)
+			assert.Positive(t, result.Duration)
+		})
+	}
+}
+
+func TestRun_GRPCCheckDefaultTimeout(t *testing.T) {
+	address := newGRPCHealthStandIn(t)
+	suite := &config.TestSuite{
+		Endpoints: []config.Endpoint{
+			{Type: config.TypeGRPC, Path: "/grpc", GRPC: &config.GRPCCheck{Address: address, Service: "hanging", Plaintext: true}},
+		},
+	}
+	started := time.Now()
+	report, err := Run(context.Background(), suite, Options{Timeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(started), 2*time.Second, "the default timeout should bound the call")
+	assert.Empty(t, report.Results)
+	require.Len(t, report.Unreachable, 1)
+	assert.Contains(t, report.Unreachable[0].Message, "DeadlineExceeded")
+}
+
+func TestRun_GRPCCheckTLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert := newTestServerCertificate(t, ca, time.Time{})
+	address := newGRPCHealthStandIn(t, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert.TLSCertificate(t)},
+	})))
+	caFile := writeTestFile(t, "ca.pem", ca.CertPEM)
+
+	timeout := 2000
+	suite := &config.TestSuite{
+		Endpoints: []config.Endpoint{
+			{Type: config.TypeGRPC, Path: "/trusted", Timeout: &timeout, GRPC: &config.GRPCCheck{Address: address, TLS: &config.TLSConfig{CAFile: caFile}}},
+			{Type: config.TypeGRPC, Path: "/untrusted", Timeout: &timeout, GRPC: &config.GRPCCheck{Address: address}},
+			{Type: config.TypeGRPC, Path: "/plaintext", Timeout: &timeout, GRPC: &config.GRPCCheck{Address: address, Plaintext: true}},
+		},
+	}
+	report, err := Run(context.Background(), suite, Options{})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, "/trusted", report.Results[0].Path)
+	assert.True(t, report.Results[0].Passed, report.Results[0].Message)
+	require.Len(t, report.Unreachable, 2, "connections failing the TLS handshake should be unreachable")
+
+	suite = &config.TestSuite{
+		TLS: &config.TLSConfig{CAFile: caFile},
+		Endpoints: []config.Endpoint{
+			{Type: config.TypeGRPC, Path: "/suite", Timeout: &timeout, GRPC: &config.GRPCCheck{Address: address}},
+			{Type: config.TypeGRPC, Path: "/insecure", Timeout: &timeout, GRPC: &config.GRPCCheck{Address: address, TLS: &config.TLSConfig{InsecureSkipVerify: true}}},
+			{Type: config.TypeGRPC, Path: "/own", Timeout: &timeout, GRPC: &config.GRPCCheck{Address: address, TLS: &config.TLSConfig{ServerName: "elsewhere.test", CAFile: caFile}}},
+		},
+	}
+	report, err = Run(context.Background(), suite, Options{})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "/suite", report.Results[0].Path)
+	assert.True(t, report.Results[0].Passed, "endpoints without TLS settings should use those of the suite")
+	assert.Equal(t, "/insecure", report.Results[1].Path)
+	assert.True(t, report.Results[1].Passed)
+	require.Len(t, report.Unreachable, 1)
+	assert.Equal(t, "/own", report.Unreachable[0].Path, "endpoint TLS settings should replace those of the suite")
+}
+
+func TestGRPCCheck_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    *config.GRPCCheck
+		expected string
+	}{
+		{name: "valid", check: &config.GRPCCheck{Address: "orders:50051", ExpectedStatus: "NOT_SERVING"}},
+		{name: "missing", check: nil, expected: "grpc.address is required"},
+		{name: "without port", check: &config.GRPCCheck{Address: "orders"}, expected: "invalid grpc.address: address orders: missing port in address"},
+		{name: "plaintext with tls", check: &config.GRPCCheck{Address: "orders:50051", Plaintext: true, TLS: &config.TLSConfig{}}, expected: "grpc.plaintext and grpc.tls are mutually exclusive"},
+		{name: "unknown status", check: &config.GRPCCheck{Address: "orders:50051", ExpectedStatus: "HEALTHY"}, expected: "unknown grpc.expected-status 'HEALTHY'"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := grpcCheck{}.Validate(config.Endpoint{Type: config.TypeGRPC, GRPC: tc.check})
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
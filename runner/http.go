@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// httpCheck probes endpoints with a GET request, asserting on the status and
+// optionally the TLS certificate of the response.
+type httpCheck struct{}
+
+func (httpCheck) Validate(endpoint config.Endpoint) error {
+	return validateStream(endpoint.Stream)
+}
+
+func (httpCheck) Target(baseURL string, endpoint config.Endpoint) string {
+	return joinURL(baseURL, endpoint.Path)
+}
+
+func (httpCheck) Execute(ctx context.Context, req CheckRequest) (TestResult, error) {
+	start := time.Now()
+
+	// Copy the HTTP client to apply the endpoint timeout if specified
+	client := *req.Client
+	if req.Endpoint.Timeout != nil {
+		timeout := time.Duration(*req.Endpoint.Timeout) * time.Millisecond
+		client.Timeout = timeout
+	}
+
+	// Without a timeout, the request and the reading of the body are bounded
+	// by a default one, as bodies may be endless.
+	stream := req.Endpoint.Stream
+	switch {
+	case stream != nil:
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, streamTimeout(req.Endpoint))
+		defer cancel()
+	case client.Timeout == 0:
+		var cancel context.CancelFunc
+		ctx, cancel = req.withTimeout(ctx)
+		defer cancel()
+	}
+
+	recorder := &traceRecorder{}
+	httpReq, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, recorder.clientTrace()), "GET", req.Target, nil)
+	if err != nil {
+		return TestResult{}, err
+	}
+	if stream != nil {
+		httpReq.Header.Set("Accept", eventStreamType)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return TestResult{}, err
+	}
+	defer resp.Body.Close()
+
+	result := TestResult{
+		ExpectedStatus: req.Endpoint.ExpectedStatus,
+		HttpStatus:     resp.StatusCode,
+		ContentType:    resp.Header.Get("Content-Type"),
+		Passed:         resp.StatusCode == req.Endpoint.ExpectedStatus,
+		Certificate:    newCertificateInfo(resp.TLS),
+	}
+
+	// Event streams are read until the expected events arrive rather than
+	// to the end, which they may never reach.
+	switch {
+	case stream == nil || !isEventStream(result.ContentType):
+		body, err := readBody(resp.Body)
+		if err != nil {
+			return TestResult{}, fmt.Errorf("error reading response body: %w", err)
+		}
+		result.Body = body
+		if stream != nil && result.Passed {
+			result.Passed = false
+			result.Message = fmt.Sprintf("expected an event stream but got Content-Type '%s'", result.ContentType)
+		}
+	case result.Passed:
+		outcome, err := readStream(resp.Body, stream)
+		result.Body = outcome.Raw
+		result.Events = outcome.Events
+		if !outcome.Done {
+			result.Passed = false
+			result.Message = describeStream(stream, outcome, err)
+		}
+	}
+	bodyDone := time.Now()
+	result.Duration = bodyDone.Sub(start)
+	result.Timings = recorder.timings(bodyDone)
+
+	if req.Endpoint.Certificate != nil {
+		host := verificationHost(httpReq, req.Client.Transport)
+		if err := checkCertificate(req.Endpoint.Certificate, result.Certificate, resp.TLS, host, req.Client.Transport); err != nil {
+			result.Passed = false
+			result.Message = err.Error()
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+)
+
+// exceedsMaxLatency reports whether the result breached its latency SLO.
+func (r TestResult) exceedsMaxLatency() bool {
+	return r.MaxLatency != nil && r.Duration > *r.MaxLatency
+}
+
+// exceedsWarnLatency reports whether the result crossed its warning latency.
+func (r TestResult) exceedsWarnLatency() bool {
+	return r.WarnLatency != nil && r.Duration > *r.WarnLatency
+}
+
+// EnforceLatency marks every passing result that exceeded its max latency as
+// failed, returning an error if any of them did. Quarantined results are
+// left untouched.
+func (tr *TestReport) EnforceLatency() error {
+	breaches := 0
+	for i := range tr.Results {
+		result := &tr.Results[i]
+		if !result.Passed || result.Quarantined || !result.exceedsMaxLatency() {
+			continue
+		}
+		result.Passed = false
+		result.Message = fmt.Sprintf("took %vms, exceeding max latency of %vms", result.Duration.Milliseconds(), result.MaxLatency.Milliseconds())
+		breaches++
+	}
+	if breaches > 0 {
+		return fmt.Errorf("%d endpoint(s) exceeded their max latency", breaches)
+	}
+	return nil
+}
+
+// millisToDuration converts an optional millisecond setting into a duration.
+func millisToDuration(ms *int) *time.Duration {
+	if ms == nil {
+		return nil
+	}
+	d := time.Duration(*ms) * time.Millisecond
+	return &d
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestResult_LatencyThresholds(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       TestResult
+		expectedMax  bool
+		expectedWarn bool
+	}{
+		{
+			name:   "no thresholds",
+			result: TestResult{Duration: time.Second},
+		},
+		{
+			name:   "within both thresholds",
+			result: TestResult{Duration: 50 * time.Millisecond, WarnLatency: timePtr(100 * time.Millisecond), MaxLatency: timePtr(200 * time.Millisecond)},
+		},
+		{
+			name:         "exceeds warn threshold only",
+			result:       TestResult{Duration: 150 * time.Millisecond, WarnLatency: timePtr(100 * time.Millisecond), MaxLatency: timePtr(200 * time.Millisecond)},
+			expectedWarn: true,
+		},
+		{
+			name:         "exceeds both thresholds",
+			result:       TestResult{Duration: 250 * time.Millisecond, WarnLatency: timePtr(100 * time.Millisecond), MaxLatency: timePtr(200 * time.Millisecond)},
+			expectedMax:  true,
+			expectedWarn: true,
+		},
+		{
+			name:   "timeout is not a latency threshold",
+			result: TestResult{Duration: 250 * time.Millisecond, Timeout: timePtr(100 * time.Millisecond)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedMax, tt.result.exceedsMaxLatency())
+			assert.Equal(t, tt.expectedWarn, tt.result.exceedsWarnLatency())
+		})
+	}
+}
+
+func TestTestReport_EnforceLatency(t *testing.T) {
+	report := TestReport{
+		Timestamp: time.Now(),
+		Results: []TestResult{
+			{Target: "https://example.com/fast", Duration: 50 * time.Millisecond, MaxLatency: timePtr(100 * time.Millisecond), Passed: true},
+			{Target: "https://example.com/slow", Duration: 150 * time.Millisecond, MaxLatency: timePtr(100 * time.Millisecond), Passed: true},
+			{Target: "https://example.com/down", Duration: 150 * time.Millisecond, MaxLatency: timePtr(100 * time.Millisecond), HttpStatus: 500, ExpectedStatus: 200},
+		},
+	}
+
+	err := report.EnforceLatency()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 endpoint(s) exceeded their max latency")
+	assert.True(t, report.Results[0].Passed)
+	assert.False(t, report.Results[1].Passed)
+	assert.Contains(t, report.Results[1].Message, "exceeding max latency of 100ms")
+	assert.Empty(t, report.Results[2].Message)
+
+	assert.NoError(t, report.EnforceLatency(), "already failed results are not counted twice")
+}
@@ -1,7 +1,10 @@
 package runner
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"slices"
 	"time"
 
 	"github.com/jgfranco17/smokesweep/outputs"
@@ -9,30 +12,101 @@ import (
 
 type TestResult struct {
 	// Target is the URL of the endpoint that was tested.
-	Target string
+	Target string `json:"target"`
+
+	// Path is the configured path of the endpoint that was tested.
+	Path string `json:"path"`
 
 	// Duration is the time it took to test the endpoint.
-	Duration time.Duration
+	Duration time.Duration `json:"duration_ns"`
 
 	// Timeout is the timeout for the test.
-	Timeout *time.Duration
+	Timeout *time.Duration `json:"timeout_ns,omitempty"`
+
+	// MaxLatency is the latency SLO of the endpoint.
+	MaxLatency *time.Duration `json:"max_latency_ns,omitempty"`
+
+	// WarnLatency is the latency above which a warning is reported.
+	WarnLatency *time.Duration `json:"warn_latency_ns,omitempty"`
 
 	// HttpStatus is the HTTP status code of the response.
-	HttpStatus int
+	HttpStatus int `json:"http_status"`
+
+	// ContentType is the Content-Type header of the response.
+	ContentType string `json:"content_type,omitempty"`
+
+	// Body is the start of the response body, up to MaxBodyCapture bytes.
+	Body []byte `json:"-"`
 
 	// ExpectedStatus is the expected HTTP status code of the response.
-	ExpectedStatus int
+	ExpectedStatus int `json:"expected_status"`
 
 	// Passed is true if the test passed, false otherwise.
-	Passed bool
+	Passed bool `json:"passed"`
+
+	// Message describes why the test failed, if not due to a status mismatch.
+	Message string `json:"message,omitempty"`
+
+	// Certificate holds the leaf certificate details for HTTPS targets.
+	Certificate *CertificateInfo `json:"certificate,omitempty"`
+
+	// Timings is the per-phase breakdown of the request duration.
+	Timings *PhaseTimings `json:"timings,omitempty"`
+
+	// Events is the number of Server-Sent Events received from a stream.
+	Events int `json:"events,omitempty"`
+
+	// Metrics are named measurements reported by exec plugins.
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+
+	// Stats aggregates the samples of an endpoint tested more than once.
+	Stats *LatencyStats `json:"stats,omitempty"`
+
+	// Flakiness scores how often the outcome of the endpoint flips, from 0
+	// for a consistent outcome to 1 for an outcome that always flips.
+	Flakiness float64 `json:"flakiness,omitempty"`
+
+	// Flaky is true if the flakiness reached the flaky threshold.
+	Flaky bool `json:"flaky,omitempty"`
+
+	// Quarantined is true if failures of the endpoint do not fail the run.
+	Quarantined bool `json:"quarantined,omitempty"`
 }
 
 type TestReport struct {
 	// Timestamp is the timestamp of the test.
-	Timestamp time.Time
+	Timestamp time.Time `json:"timestamp"`
 
 	// Results is the list of test results.
-	Results []TestResult
+	Results []TestResult `json:"results"`
+
+	// Unreachable is the list of endpoints that could not be reached at all.
+	Unreachable []TestResult `json:"unreachable,omitempty"`
+
+	// BaselineChanges lists the changes since a baseline report, if one was
+	// compared against.
+	BaselineChanges []BaselineChange `json:"baseline_changes,omitempty"`
+}
+
+// Passed reports whether every endpoint that is not quarantined was reached
+// and passed.
+func (tr *TestReport) Passed() bool {
+	for _, result := range slices.Concat(tr.Results, tr.Unreachable) {
+		if !result.Passed && !result.Quarantined {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteJSON writes the test report as indented JSON.
+func (tr *TestReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(tr); err != nil {
+		return fmt.Errorf("error encoding test report: %w", err)
+	}
+	return nil
 }
 
 /*
@@ -46,16 +120,66 @@ func (tr *TestReport) SummarizeResults() error {
 	}
 	fmt.Println("------------------------------")
 	for _, result := range tr.Results {
+		if cert := result.Certificate; cert != nil && cert.ExpiryWarning {
+			outputs.PrintColoredMessage("yellow", "CERT", "%s certificate expires in %d days (%s)", result.Target, cert.DaysRemaining(), cert.NotAfter.Format(time.DateOnly))
+		}
+		if result.Flaky {
+			outputs.PrintColoredMessage("yellow", "FLAKY", "%s (%vms) %s with flakiness %.2f%s", result.Target, result.Duration.Milliseconds(), outcome(result.Passed), result.Flakiness, quarantineNote(result.Quarantined))
+			continue
+		}
 		if result.Passed {
-			if result.Timeout != nil {
-				if !(result.Duration < *result.Timeout) {
-					outputs.PrintColoredMessage("yellow", "SLOW", "%s (%vms) exceeded threshold", result.Target, result.Duration.Milliseconds())
-				}
+			switch {
+			case result.exceedsMaxLatency():
+				outputs.PrintColoredMessage("red", "SLOW", "%s (%vms) exceeded max latency of %vms", result.Target, result.Duration.Milliseconds(), result.MaxLatency.Milliseconds())
+			case result.exceedsWarnLatency():
+				outputs.PrintColoredMessage("yellow", "SLOW", "%s (%vms) exceeded warn latency of %vms", result.Target, result.Duration.Milliseconds(), result.WarnLatency.Milliseconds())
+			default:
+				outputs.PrintColoredMessage("green", "SUCCESS", "%s (%vms) OK", result.Target, result.Duration.Milliseconds())
 			}
-			outputs.PrintColoredMessage("green", "SUCCESS", "%s (%vms) OK", result.Target, result.Duration.Milliseconds())
+		} else if result.Message != "" {
+			outputs.PrintColoredMessage("red", "FAILED", "Target '%s' %s%s", result.Target, result.Message, quarantineNote(result.Quarantined))
 		} else {
-			outputs.PrintColoredMessage("red", "FAILED", "Target '%s' expected HTTP status %d but got %d", result.Target, result.ExpectedStatus, result.HttpStatus)
+			outputs.PrintColoredMessage("red", "FAILED", "Target '%s' expected HTTP status %d but got %d%s", result.Target, result.ExpectedStatus, result.HttpStatus, quarantineNote(result.Quarantined))
 		}
 	}
+	tr.printStatsTable()
+	tr.printBaselineChanges()
 	return nil
 }
+
+func outcome(passed bool) string {
+	if passed {
+		return "passed"
+	}
+	return "failed"
+}
+
+func quarantineNote(quarantined bool) string {
+	if quarantined {
+		return " (quarantined)"
+	}
+	return ""
+}
+
+// printStatsTable prints the latency distribution of sampled endpoints.
+func (tr *TestReport) printStatsTable() {
+	var rows [][]string
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%vms", d.Milliseconds())
+	}
+	for _, result := range tr.Results {
+		if stats := result.Stats; stats != nil {
+			rows = append(rows, []string{
+				result.Target,
+				fmt.Sprintf("%d/%d", stats.Successes, stats.Samples),
+				fmt.Sprintf("%.0f%%", stats.SuccessRatio()*100),
+				ms(stats.Min), ms(stats.Mean), ms(stats.P50), ms(stats.P90), ms(stats.P99), ms(stats.Max),
+			})
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	fmt.Println("------------------------------")
+	outputs.PrintTable([]string{"TARGET", "OK", "RATIO", "MIN", "MEAN", "P50", "P90", "P99", "MAX"}, rows)
+}
@@ -3,6 +3,7 @@ package runner
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"path"
 	"strings"
@@ -17,24 +18,116 @@ import (
 
 const (
 	DefaultConfigFile string = ".smokesweep.yaml"
+
+	// MaxBodyCapture is the maximum number of response body bytes kept in a result.
+	MaxBodyCapture int64 = 1 << 20
+
+	// DefaultConcurrency is the default maximum number of requests in flight.
+	DefaultConcurrency int = 10
+
+	// DefaultTimeout bounds probes of endpoints without a timeout of their
+	// own when the context of the run has no deadline.
+	DefaultTimeout time.Duration = 30 * time.Second
 )
 
+// Options controls how Run executes a test suite.
+type Options struct {
+	// FailFast stops the run on the first failure of an endpoint that is not
+	// quarantined, returning a *FailureError.
+	FailFast bool
+
+	// Concurrency is the maximum number of requests in flight. Defaults to
+	// DefaultConcurrency.
+	Concurrency int
+
+	// Client sends the requests. Defaults to a client applying the TLS
+	// settings of the suite. Endpoint timeouts override its timeout.
+	Client *http.Client
+
+	// Timeout bounds probes of endpoints without a timeout of their own when
+	// ctx has no deadline. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// Logger receives progress logs. Defaults to discarding them.
+	Logger *logrus.Logger
+
+	// OnUnreachable is called whenever a target cannot be reached.
+	OnUnreachable func(target string, err error)
+}
+
+// FailureError is returned by a fail-fast run for the first failing endpoint.
+type FailureError struct {
+	// Result is the failing result. It has no HTTP status if the target
+	// could not be reached.
+	Result TestResult
+
+	// Err is the error reaching the target, if it could not be reached.
+	Err error
+}
+
+func (e *FailureError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("failed to reach target %s: %v", e.Result.Target, e.Err)
+	case e.Result.Message != "":
+		return fmt.Sprintf("target %s failed: %s", e.Result.Target, e.Result.Message)
+	default:
+		return fmt.Sprintf("target %s expected HTTP %d but got %d", e.Result.Target, e.Result.ExpectedStatus, e.Result.HttpStatus)
+	}
+}
+
+func (e *FailureError) Unwrap() error {
+	return e.Err
+}
+
 // job represents a single test job to be executed
 type job struct {
-	Endpoint config.Endpoint
-	Target   string
-	Index    int
+	CheckRequest
+	Check Check
+	Index int
 }
 
 // IndexedResult wraps TestResult with an index for ordering
 type IndexedResult struct {
 	Result TestResult
 	Index  int
+	Err    error
 }
 
-// Execute runs the provided test suite asynchronously and returns the test report.
+// Execute runs the provided test suite asynchronously and returns the test
+// report, logging to the logger of the context and printing unreachable
+// targets.
 func Execute(ctx context.Context, conf *config.TestSuite, failFast bool) (TestReport, error) {
-	logger := logging.FromContext(ctx)
+	WarnInsecure(conf)
+	return Run(ctx, conf, Options{
+		FailFast: failFast,
+		Logger:   logging.FromContext(ctx),
+		OnUnreachable: func(target string, err error) {
+			outputs.PrintColoredMessage("red", "UNREACHABLE", "Failed to reach target %s", target)
+		},
+	})
+}
+
+// Run executes the test suite with the given options and returns the test
+// report. It has no side effects beyond the requests and the options.
+func Run(ctx context.Context, conf *config.TestSuite, opts Options) (TestReport, error) {
+	if opts.Concurrency < 0 {
+		return TestReport{}, fmt.Errorf("concurrency must not be negative, got %d", opts.Concurrency)
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.Timeout < 0 {
+		return TestReport{}, fmt.Errorf("timeout must not be negative, got %v", opts.Timeout)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Logger == nil {
+		opts.Logger = logrus.New()
+		opts.Logger.SetOutput(io.Discard)
+	}
+	logger := opts.Logger
 	logger.WithFields(logrus.Fields{
 		"count": len(conf.Endpoints),
 		"url":   conf.URL,
@@ -50,33 +143,64 @@ func Execute(ctx context.Context, conf *config.TestSuite, failFast bool) (TestRe
 		}, nil
 	}
 
-	jobChan := make(chan job, len(conf.Endpoints))
-	resultChan := make(chan IndexedResult, len(conf.Endpoints))
-	errorChan := make(chan error, len(conf.Endpoints))
+	client := opts.Client
+	if client == nil {
+		transport, err := newTransport(conf.TLS)
+		if err != nil {
+			return TestReport{}, fmt.Errorf("error configuring TLS: %w", err)
+		}
+		client = &http.Client{Transport: transport}
+	}
+
+	if err := validatePaths(conf.Endpoints); err != nil {
+		return TestReport{}, err
+	}
+	endpointChecks := make([]Check, len(conf.Endpoints))
+	sampleCounts := make([]int, len(conf.Endpoints))
+	totalJobs := 0
+	for i, endpoint := range conf.Endpoints {
+		check, err := validateEndpoint(endpoint)
+		if err != nil {
+			return TestReport{}, fmt.Errorf("invalid endpoint %s: %w", endpoint.Path, err)
+		}
+		endpointChecks[i] = check
+		sampleCounts[i] = sampleCount(conf, endpoint)
+		totalJobs += sampleCounts[i]
+	}
+
+	jobChan := make(chan job, totalJobs)
+	resultChan := make(chan IndexedResult, totalJobs)
+	errorChan := make(chan error, totalJobs)
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	//We limit max workers to prevent resource exhaustion.
-	numWorkers := len(conf.Endpoints)
-	if numWorkers > 10 {
-		numWorkers = 10
-	}
+	numWorkers := min(totalJobs, opts.Concurrency)
 
 	var wg sync.WaitGroup
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
-		go worker(ctx, &wg, jobChan, resultChan, errorChan, logger, failFast)
+		go worker(ctx, &wg, jobChan, resultChan, errorChan, opts)
 	}
 
 	go func() {
 		defer close(jobChan)
 		for i, endpoint := range conf.Endpoints {
-			target := joinURL(conf.URL, endpoint.Path)
-			select {
-			case jobChan <- job{Endpoint: endpoint, Target: target, Index: i}:
-			case <-ctx.Done():
-				return
+			request := CheckRequest{
+				Endpoint: endpoint,
+				BaseURL:  conf.URL,
+				Target:   endpointChecks[i].Target(conf.URL, endpoint),
+				Client:   client,
+				TLS:      conf.TLS,
+				Timeout:  opts.Timeout,
+			}
+			for sample := 0; sample < sampleCounts[i]; sample++ {
+				select {
+				case jobChan <- job{CheckRequest: request, Check: endpointChecks[i], Index: i}:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
@@ -87,56 +211,71 @@ func Execute(ctx context.Context, conf *config.TestSuite, failFast bool) (TestRe
 		close(errorChan)
 	}()
 
-	results := make([]TestResult, len(conf.Endpoints))
+	samples := make([][]TestResult, len(conf.Endpoints))
+	unreachable := make([]int, len(conf.Endpoints))
+	reachErrors := make([]error, len(conf.Endpoints))
 	completed := 0
-	hasError := false
 
-	for completed < len(conf.Endpoints) {
+	for completed < totalJobs {
 		select {
 		case result, ok := <-resultChan:
 			if !ok {
 				// Channel closed, all workers done
-				break
+				resultChan = nil
+				continue
+			}
+			if result.Err != nil {
+				unreachable[result.Index]++
+				reachErrors[result.Index] = result.Err
+			} else {
+				samples[result.Index] = append(samples[result.Index], result.Result)
 			}
-			results[result.Index] = result.Result
 			completed++
 
 		case err, ok := <-errorChan:
 			if !ok {
-				// Channel closed, all workers done
-				break
-			}
-			hasError = true
-			if failFast {
-				cancel() // Cancel all remaining workers
-				return TestReport{}, err
+				errorChan = nil
+				continue
 			}
-			completed++
+			cancel() // Cancel all remaining workers
+			return TestReport{}, err
 
 		case <-ctx.Done():
 			return TestReport{}, ctx.Err()
 		}
 	}
 
-	if hasError && !failFast {
-		filteredResults := make([]TestResult, 0, len(results))
-		for _, result := range results {
-			if result.Target != "" {
-				filteredResults = append(filteredResults, result)
-			}
+	// Endpoints that could not be reached at all are reported separately.
+	results := make([]TestResult, 0, len(conf.Endpoints))
+	var unreachableResults []TestResult
+	for i, endpointSamples := range samples {
+		switch {
+		case len(endpointSamples) == 0:
+			unreachableResults = append(unreachableResults, TestResult{
+				Target:         endpointChecks[i].Target(conf.URL, conf.Endpoints[i]),
+				Path:           conf.Endpoints[i].Path,
+				ExpectedStatus: conf.Endpoints[i].ExpectedStatus,
+				Message:        reachErrors[i].Error(),
+				Quarantined:    conf.Endpoints[i].Quarantine,
+			})
+		case sampleCounts[i] == 1:
+			results = append(results, endpointSamples[0])
+		default:
+			results = append(results, aggregateSamples(endpointSamples, unreachable[i], conf.Endpoints[i]))
 		}
-		results = filteredResults
 	}
 
 	return TestReport{
-		Timestamp: testRunStartTime,
-		Results:   results,
+		Timestamp:   testRunStartTime,
+		Results:     results,
+		Unreachable: unreachableResults,
 	}, nil
 }
 
 // worker processes test jobs from the job channel
-func worker(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan job, resultChan chan<- IndexedResult, errorChan chan<- error, logger *logrus.Logger, failFast bool) {
+func worker(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan job, resultChan chan<- IndexedResult, errorChan chan<- error, opts Options) {
 	defer wg.Done()
+	logger := opts.Logger
 
 	for {
 		select {
@@ -151,15 +290,45 @@ func worker(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan job, resultC
 
 			result, err := executeSingleTest(ctx, job)
 			if err != nil {
-				outputs.PrintColoredMessage("red", "UNREACHABLE", "Failed to reach target %s", job.Target)
-				errorChan <- fmt.Errorf("failed to reach target %s: %w", job.Target, err)
-				return
+				if opts.OnUnreachable != nil {
+					opts.OnUnreachable(job.Target, err)
+				}
+				err = &FailureError{
+					Result: TestResult{
+						Target:         job.Target,
+						Path:           job.Endpoint.Path,
+						ExpectedStatus: job.Endpoint.ExpectedStatus,
+						Message:        err.Error(),
+					},
+					Err: err,
+				}
+				if opts.FailFast && !job.Endpoint.Quarantine {
+					errorChan <- err
+					return
+				}
+				select {
+				case resultChan <- IndexedResult{Index: job.Index, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+				continue
 			}
 
-			// Check for status code mismatch
-			if result.HttpStatus != result.ExpectedStatus {
-				if failFast {
-					errorChan <- fmt.Errorf("target %s expected HTTP %d but got %d", job.Target, result.ExpectedStatus, result.HttpStatus)
+			if timings := result.Timings; timings != nil {
+				logger.WithFields(logrus.Fields{
+					"target":   job.Target,
+					"dns":      timings.DNS,
+					"connect":  timings.Connect,
+					"tls":      timings.TLS,
+					"ttfb":     timings.TimeToFirstByte,
+					"transfer": timings.Transfer,
+				}).Debug("Request phase timings")
+			}
+
+			// Check for failed assertions
+			if !result.Passed {
+				if opts.FailFast && !job.Endpoint.Quarantine {
+					errorChan <- &FailureError{Result: result}
 					return
 				}
 				// For non-fail-fast, still send the result but mark it as failed
@@ -177,56 +346,84 @@ func worker(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan job, resultC
 	}
 }
 
-// executeSingleTest executes a single test and returns the result
-func executeSingleTest(ctx context.Context, j job) (TestResult, error) {
-	start := time.Now()
-
-	// Create HTTP client with timeout if specified
-	client := &http.Client{}
-	if j.Endpoint.Timeout != nil {
-		timeout := time.Duration(*j.Endpoint.Timeout) * time.Millisecond
-		client.Timeout = timeout
+// Validate checks the configuration of the suite as Run does before
+// executing it.
+func Validate(conf *config.TestSuite) error {
+	if err := validatePaths(conf.Endpoints); err != nil {
+		return err
 	}
+	for _, endpoint := range conf.Endpoints {
+		if _, err := validateEndpoint(endpoint); err != nil {
+			return fmt.Errorf("invalid endpoint %s: %w", endpoint.Path, err)
+		}
+	}
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", j.Target, nil)
-	if err != nil {
-		return TestResult{}, err
+// validatePaths checks that every endpoint has a path of its own, as results,
+// history and metrics identify endpoints by their path.
+func validatePaths(endpoints []config.Endpoint) error {
+	seen := make(map[string]bool, len(endpoints))
+	for i, endpoint := range endpoints {
+		if endpoint.Path == "" {
+			return fmt.Errorf("invalid endpoint #%d: path is required", i+1)
+		}
+		if seen[endpoint.Path] {
+			return fmt.Errorf("invalid endpoint %s: path is used by another endpoint", endpoint.Path)
+		}
+		seen[endpoint.Path] = true
 	}
+	return nil
+}
 
-	resp, err := client.Do(req)
+// validateEndpoint checks the configuration of an endpoint before a run and
+// returns its check.
+func validateEndpoint(endpoint config.Endpoint) (Check, error) {
+	if err := validateLatencyPercentile(endpoint.LatencyPercentile); err != nil {
+		return nil, err
+	}
+	check, err := checkFor(endpoint)
 	if err != nil {
-		return TestResult{}, err
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	duration := time.Since(start)
-
-	result := TestResult{
-		Target:         j.Target,
-		Duration:       duration,
-		ExpectedStatus: j.Endpoint.ExpectedStatus,
-		HttpStatus:     resp.StatusCode,
-		Passed:         resp.StatusCode == j.Endpoint.ExpectedStatus,
+	if err := check.Validate(endpoint); err != nil {
+		return nil, err
 	}
+	return check, nil
+}
 
-	if j.Endpoint.Timeout != nil {
-		d := time.Duration(*j.Endpoint.Timeout) * time.Millisecond
-		result.Timeout = &d
+// executeSingleTest executes a single test with the check of the job and
+// fills in the fields common to every check.
+func executeSingleTest(ctx context.Context, j job) (TestResult, error) {
+	result, err := j.Check.Execute(ctx, j.CheckRequest)
+	if err != nil {
+		return TestResult{}, err
 	}
-
+	result.Target = j.Target
+	result.Path = j.Endpoint.Path
+	result.Quarantined = j.Endpoint.Quarantine
+	result.Timeout = millisToDuration(j.Endpoint.Timeout)
+	result.MaxLatency = millisToDuration(j.Endpoint.MaxLatency)
+	result.WarnLatency = millisToDuration(j.Endpoint.WarnLatency)
 	return result, nil
 }
 
 // PingURL make a simple GET request to a provided URL for liveness.
-func PingURL(ctx context.Context, url string, timeout time.Duration) error {
+func PingURL(ctx context.Context, url string, timeout time.Duration, tlsConf *config.TLSConfig) error {
 	logger := logging.FromContext(ctx).WithFields(
 		logrus.Fields{
 			"url":     url,
 			"timeout": timeout,
 		},
 	)
+	warnInsecure(tlsConf)
+	transport, err := newTransport(tlsConf)
+	if err != nil {
+		return fmt.Errorf("error configuring TLS: %w", err)
+	}
 	client := &http.Client{
-		Timeout: timeout,
+		Transport: transport,
+		Timeout:   timeout,
 	}
 
 	start := time.Now()
@@ -249,6 +446,12 @@ func PingURL(ctx context.Context, url string, timeout time.Duration) error {
 	return nil
 }
 
+// readBody reads the response body up to MaxBodyCapture bytes. The rest is
+// left unread, as bodies may be endless.
+func readBody(body io.Reader) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(body, MaxBodyCapture))
+}
+
 func joinURL(base string, paths ...string) string {
 	p := path.Join(paths...)
 	return fmt.Sprintf("%s/%s", strings.TrimRight(base, "/"), strings.TrimLeft(p, "/"))
@@ -3,6 +3,7 @@ package runner
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/jgfranco17/dev-tooling-go/logging"
 	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/mock"
 	"github.com/sirupsen/logrus"
 
 	"github.com/stretchr/testify/assert"
@@ -181,6 +183,49 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestExecute_UnreachableReported(t *testing.T) {
+	ctx, _ := newContextWithLogger(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Close()
+
+	suite := newMockConfig(server.URL, []config.Endpoint{
+		{Path: "/users", ExpectedStatus: 200},
+	})
+
+	report, err := Execute(ctx, suite, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Results)
+	require.Len(t, report.Unreachable, 1)
+	assert.Equal(t, "/users", report.Unreachable[0].Path)
+	assert.Equal(t, server.URL+"/users", report.Unreachable[0].Target)
+	assert.False(t, report.Unreachable[0].Passed)
+	assert.Contains(t, report.Unreachable[0].Message, "failed to reach target")
+}
+
+func TestExecute_AgainstMockServer(t *testing.T) {
+	ctx, _ := newContextWithLogger(t)
+	suite := newMockConfig("", []config.Endpoint{
+		{Path: "/users", ExpectedStatus: 200},
+		{Path: "/slow", ExpectedStatus: 200, MaxLatency: intPtr(10), Mock: &config.MockResponse{Latency: intPtr(30)}},
+		{Path: "/gone", ExpectedStatus: 410},
+	})
+	handler, err := mock.NewHandler(suite)
+	require.NoError(t, err)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	suite.URL = server.URL
+
+	report, err := Execute(ctx, suite, false)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 3)
+	for _, result := range report.Results {
+		assert.True(t, result.Passed, result.Path)
+	}
+	assert.True(t, report.Results[1].exceedsMaxLatency())
+}
+
 func TestPingURL(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -254,7 +299,7 @@ func TestPingURL(t *testing.T) {
 				url = server.URL
 			}
 
-			err := PingURL(ctx, url, tt.timeout)
+			err := PingURL(ctx, url, tt.timeout, nil)
 
 			if tt.expectedError != "" {
 				require.Error(t, err)
@@ -499,6 +544,34 @@ func TestTestReport_Fields(t *testing.T) {
 	assert.Len(t, report.Results, 2)
 }
 
+func TestTestReport_WriteJSON(t *testing.T) {
+	timeout := 500 * time.Millisecond
+	report := TestReport{
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Results: []TestResult{
+			{
+				Target:         "https://example.com/users",
+				Duration:       100 * time.Millisecond,
+				Timeout:        &timeout,
+				HttpStatus:     200,
+				ExpectedStatus: 200,
+				Passed:         true,
+				Timings:        &PhaseTimings{TimeToFirstByte: 80 * time.Millisecond},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+	assert.Contains(t, buf.String(), `"timestamp": "2025-01-02T03:04:05Z"`)
+	assert.Contains(t, buf.String(), `"duration_ns": 100000000`)
+	assert.Contains(t, buf.String(), `"ttfb_ns": 80000000`)
+
+	var decoded TestReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, report.Results, decoded.Results)
+}
+
 // Helper functions
 func intPtr(i int) *int {
 	return &i
@@ -535,6 +608,50 @@ func TestTestReport_SummarizeResults_Output(t *testing.T) {
 }
 
 // Test edge cases for Execute function
+func TestValidate(t *testing.T) {
+	tcp := func(path, address string) config.Endpoint {
+		return config.Endpoint{Type: config.TypeTCP, Path: path, TCP: &config.TCPCheck{Address: address}}
+	}
+	tests := []struct {
+		name        string
+		endpoints   []config.Endpoint
+		expectedErr string
+	}{
+		{
+			name:      "unique paths",
+			endpoints: []config.Endpoint{tcp("/db", "127.0.0.1:5432"), tcp("/cache", "127.0.0.1:6379")},
+		},
+		{
+			name:        "missing path",
+			endpoints:   []config.Endpoint{tcp("/db", "127.0.0.1:5432"), tcp("", "127.0.0.1:6379")},
+			expectedErr: "invalid endpoint #2: path is required",
+		},
+		{
+			name:        "duplicate path",
+			endpoints:   []config.Endpoint{tcp("/db", "127.0.0.1:5432"), tcp("/db", "127.0.0.1:5433")},
+			expectedErr: "invalid endpoint /db: path is used by another endpoint",
+		},
+		{
+			name:        "invalid endpoint",
+			endpoints:   []config.Endpoint{tcp("/db", "")},
+			expectedErr: "invalid endpoint /db: tcp.address is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := &config.TestSuite{Endpoints: tt.endpoints}
+			err := Validate(suite)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+			_, err = Run(context.Background(), suite, Options{})
+			assert.EqualError(t, err, tt.expectedErr, "Run should reject the suite before any request")
+		})
+	}
+}
+
 func TestExecute_EdgeCases(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// eventStreamType is the media type of Server-Sent Events streams.
+const eventStreamType string = "text/event-stream"
+
+// DefaultStreamTimeout bounds the reading of event streams of endpoints
+// without a stream or endpoint timeout, as streams may never end.
+const DefaultStreamTimeout = 30 * time.Second
+
+// event is a dispatched Server-Sent Event.
+type event struct {
+	Type string
+	Data string
+}
+
+// streamOutcome is what was read from an event stream.
+type streamOutcome struct {
+	// Events is the number of events received.
+	Events int
+
+	// Raw is the start of the stream, up to MaxBodyCapture bytes.
+	Raw []byte
+
+	// Done is true if the stream met the expectation of the check.
+	Done bool
+}
+
+// validateStream checks the stream settings of an endpoint.
+func validateStream(conf *config.StreamCheck) error {
+	if conf == nil {
+		return nil
+	}
+	if conf.Events < 0 {
+		return fmt.Errorf("stream.events must not be negative, got %d", conf.Events)
+	}
+	if conf.Events > 0 && (conf.EventType != "" || conf.DataContains != "") {
+		return errors.New("stream.events cannot be combined with stream.event-type or stream.data-contains")
+	}
+	return nil
+}
+
+// streamTimeout returns how long to read the event stream of the endpoint
+// for. The endpoint timeout also applies to streams through the client.
+func streamTimeout(endpoint config.Endpoint) time.Duration {
+	switch {
+	case endpoint.Stream.Timeout != nil:
+		return time.Duration(*endpoint.Stream.Timeout) * time.Millisecond
+	case endpoint.Timeout != nil:
+		return time.Duration(*endpoint.Timeout) * time.Millisecond
+	default:
+		return DefaultStreamTimeout
+	}
+}
+
+// isEventStream reports whether the Content-Type is an event stream.
+func isEventStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == eventStreamType
+}
+
+// readStream reads events from the stream until the expectation of the check
+// is met or the stream ends. A read error, such as the deadline of the
+// request passing, is returned with what was read so far.
+func readStream(body io.Reader, conf *config.StreamCheck) (streamOutcome, error) {
+	var outcome streamOutcome
+	want := conf.Events
+	if want == 0 {
+		want = 1
+	}
+	matching := conf.EventType != "" || conf.DataContains != ""
+
+	reader := bufio.NewReader(body)
+	var current event
+	var data []string
+	for {
+		line, err := reader.ReadString('\n')
+		if remaining := int(MaxBodyCapture) - len(outcome.Raw); remaining > 0 {
+			outcome.Raw = append(outcome.Raw, line[:min(len(line), remaining)]...)
+		}
+		if err != nil {
+			// A partial line at the end of the stream is not a complete field.
+			if errors.Is(err, io.EOF) {
+				return outcome, nil
+			}
+			return outcome, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if len(data) == 0 {
+				current = event{}
+				continue
+			}
+			current.Data = strings.Join(data, "\n")
+			if current.Type == "" {
+				current.Type = "message"
+			}
+			outcome.Events++
+			if matching && eventMatches(current, conf) || !matching && outcome.Events >= want {
+				outcome.Done = true
+				return outcome, nil
+			}
+			current, data = event{}, nil
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			current.Type = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
+
+// eventMatches reports whether the event has the type and data the check
+// waits for.
+func eventMatches(e event, conf *config.StreamCheck) bool {
+	if conf.EventType != "" && e.Type != conf.EventType {
+		return false
+	}
+	return conf.DataContains == "" || strings.Contains(e.Data, conf.DataContains)
+}
+
+// describeStream describes why a stream did not meet the expectation of the
+// check.
+func describeStream(conf *config.StreamCheck, outcome streamOutcome, err error) string {
+	var expected string
+	switch {
+	case conf.EventType != "" && conf.DataContains != "":
+		expected = fmt.Sprintf("no %s event with data containing %q", conf.EventType, conf.DataContains)
+	case conf.EventType != "":
+		expected = fmt.Sprintf("no %s event", conf.EventType)
+	case conf.DataContains != "":
+		expected = fmt.Sprintf("no event with data containing %q", conf.DataContains)
+	default:
+		expected = fmt.Sprintf("received %d of %d events", outcome.Events, max(conf.Events, 1))
+	}
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return fmt.Sprintf("%s before the stream ended", expected)
+	case errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Sprintf("%s before the deadline", expected)
+	default:
+		return fmt.Sprintf("%s before the stream failed: %v", expected, err)
+	}
+}
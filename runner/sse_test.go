@@ -0,0 +1,170 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSSEStandIn serves a notification stream that never ends on its own:
+// /notifications sends heartbeats and an order event, /short sends two
+// events and ends, and /json is not a stream. The returned channel receives
+// whenever a notification stream is closed by the client.
+func newSSEStandIn(t *testing.T) (*httptest.Server, <-chan struct{}) {
+	t.Helper()
+	closed := make(chan struct{}, 16)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		flusher := w.(http.Flusher)
+		fmt.Fprint(w, ": connected\r\n\r\n")
+		flusher.Flush()
+		for i := 0; ; i++ {
+			if i == 3 {
+				fmt.Fprint(w, "event: order\ndata: {\"id\": 42,\ndata: \"total\": 9.5}\n\n")
+			} else {
+				fmt.Fprintf(w, "event: heartbeat\ndata: %d\n\n", i)
+			}
+			flusher.Flush()
+			select {
+			case <-r.Context().Done():
+				closed <- struct{}{}
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	})
+	mux.HandleFunc("/short", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: first\n\ndata: second\n\n")
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"events": []}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, closed
+}
+
+func TestRun_StreamCheck(t *testing.T) {
+	server, closed := newSSEStandIn(t)
+	streamTimeout := 200
+
+	tests := []struct {
+		name    string
+		path    string
+		stream  config.StreamCheck
+		passed  bool
+		events  int
+		message string
+	}{
+		{name: "number of events", path: "/notifications", stream: config.StreamCheck{Events: 3}, passed: true, events: 3},
+		{name: "first event by default", path: "/notifications", passed: true, events: 1},
+		{name: "event type", path: "/notifications", stream: config.StreamCheck{EventType: "order"}, passed: true, events: 4},
+		{name: "multi-line data", path: "/notifications", stream: config.StreamCheck{DataContains: "42,\n\"total\""}, passed: true, events: 4},
+		{
+			name:    "no matching event before deadline",
+			path:    "/notifications",
+			stream:  config.StreamCheck{EventType: "refund", Timeout: &streamTimeout},
+			message: "no refund event before the deadline",
+		},
+		{
+			name:    "stream ends early",
+			path:    "/short",
+			stream:  config.StreamCheck{Events: 5},
+			events:  2,
+			message: "received 2 of 5 events before the stream ended",
+		},
+		{
+			name:    "not a stream",
+			path:    "/json",
+			message: "expected an event stream but got Content-Type 'application/json'",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := tc.stream
+			suite := &config.TestSuite{
+				URL:       server.URL,
+				Endpoints: []config.Endpoint{{Path: tc.path, ExpectedStatus: 200, Stream: &stream}},
+			}
+			started := time.Now()
+			report, err := Run(context.Background(), suite, Options{})
+			require.NoError(t, err)
+			assert.Less(t, time.Since(started), 2*time.Second, "the stream should be closed by the check")
+			require.Len(t, report.Results, 1)
+			result := report.Results[0]
+			assert.Equal(t, tc.passed, result.Passed, result.Message)
+			assert.Equal(t, tc.message, result.Message)
+			if tc.events > 0 {
+				assert.Equal(t, tc.events, result.Events)
+			}
+		})
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the server should see the stream closed")
+	}
+}
+
+func TestReadStream(t *testing.T) {
+	stream := strings.Join([]string{
+		": comment",
+		"retry: 1000",
+		"",
+		"data: plain",
+		"",
+		"event: update",
+		"id: 7",
+		"data:no space",
+		"data",
+		"",
+		"event: ignored without data",
+		"",
+		"data: partial",
+	}, "\n")
+
+	outcome, err := readStream(strings.NewReader(stream), &config.StreamCheck{Events: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, outcome.Events, "events without data and partial events should not be dispatched")
+	assert.False(t, outcome.Done)
+	assert.Equal(t, stream, string(outcome.Raw))
+
+	outcome, err = readStream(strings.NewReader(stream), &config.StreamCheck{EventType: "message"})
+	require.NoError(t, err)
+	assert.True(t, outcome.Done, "events without a type should be message events")
+	assert.Equal(t, 1, outcome.Events)
+
+	outcome, err = readStream(strings.NewReader(stream), &config.StreamCheck{EventType: "update", DataContains: "no space\n"})
+	require.NoError(t, err)
+	assert.True(t, outcome.Done, "data lines should be joined with newlines")
+}
+
+func TestStreamTimeout(t *testing.T) {
+	endpointTimeout, readTimeout := 2000, 500
+	assert.Equal(t, DefaultStreamTimeout, streamTimeout(config.Endpoint{Stream: &config.StreamCheck{}}), "streams should never be read without a deadline")
+	assert.Equal(t, 2*time.Second, streamTimeout(config.Endpoint{Timeout: &endpointTimeout, Stream: &config.StreamCheck{}}))
+	assert.Equal(t, 500*time.Millisecond, streamTimeout(config.Endpoint{Timeout: &endpointTimeout, Stream: &config.StreamCheck{Timeout: &readTimeout}}))
+}
+
+func TestValidateStream(t *testing.T) {
+	assert.NoError(t, validateStream(nil))
+	assert.NoError(t, validateStream(&config.StreamCheck{EventType: "order", DataContains: "42"}))
+	assert.EqualError(t, validateStream(&config.StreamCheck{Events: -1}), "stream.events must not be negative, got -1")
+	assert.EqualError(t, validateStream(&config.StreamCheck{Events: 2, EventType: "order"}), "stream.events cannot be combined with stream.event-type or stream.data-contains")
+}
@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// DefaultLatencyPercentile is the aggregate latency used for sampled
+// endpoints when no percentile is configured.
+const DefaultLatencyPercentile string = "p90"
+
+// LatencyStats aggregates the outcomes of repeated samples of an endpoint.
+type LatencyStats struct {
+	// Samples is the number of times the endpoint was tested.
+	Samples int `json:"samples"`
+
+	// Successes is the number of samples that passed.
+	Successes int `json:"successes"`
+
+	// Min is the fastest sample latency.
+	Min time.Duration `json:"min_ns"`
+
+	// Max is the slowest sample latency.
+	Max time.Duration `json:"max_ns"`
+
+	// Mean is the average sample latency.
+	Mean time.Duration `json:"mean_ns"`
+
+	// P50 is the median sample latency.
+	P50 time.Duration `json:"p50_ns"`
+
+	// P90 is the 90th percentile sample latency.
+	P90 time.Duration `json:"p90_ns"`
+
+	// P99 is the 99th percentile sample latency.
+	P99 time.Duration `json:"p99_ns"`
+}
+
+// SuccessRatio returns the fraction of samples that passed.
+func (ls *LatencyStats) SuccessRatio() float64 {
+	if ls.Samples == 0 {
+		return 0
+	}
+	return float64(ls.Successes) / float64(ls.Samples)
+}
+
+// Percentile returns the aggregate latency by name, e.g. "p90" or "mean".
+func (ls *LatencyStats) Percentile(name string) (time.Duration, error) {
+	switch name {
+	case "min":
+		return ls.Min, nil
+	case "max":
+		return ls.Max, nil
+	case "mean":
+		return ls.Mean, nil
+	case "p50":
+		return ls.P50, nil
+	case "p90", "":
+		return ls.P90, nil
+	case "p99":
+		return ls.P99, nil
+	default:
+		return 0, fmt.Errorf("unknown latency-percentile '%s'", name)
+	}
+}
+
+// validateLatencyPercentile checks that the percentile name is supported.
+func validateLatencyPercentile(name string) error {
+	_, err := (&LatencyStats{}).Percentile(name)
+	return err
+}
+
+// NewLatencyStats computes the latency distribution of the given durations.
+// Successes is left for the caller to fill in.
+func NewLatencyStats(durations []time.Duration) LatencyStats {
+	stats := LatencyStats{Samples: len(durations)}
+	if len(durations) == 0 {
+		return stats
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Mean = total / time.Duration(len(sorted))
+	stats.P50 = nearestRank(sorted, 50)
+	stats.P90 = nearestRank(sorted, 90)
+	stats.P99 = nearestRank(sorted, 99)
+	return stats
+}
+
+// nearestRank returns the p-th percentile of sorted durations.
+func nearestRank(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// sampleCount returns how many times the endpoint should be tested.
+func sampleCount(conf *config.TestSuite, endpoint config.Endpoint) int {
+	if endpoint.Samples > 0 {
+		return endpoint.Samples
+	}
+	if conf.Repeat > 0 {
+		return conf.Repeat
+	}
+	return 1
+}
+
+// aggregateSamples folds the results of a repeatedly sampled endpoint into
+// a single result. The result passes only if every sample passed, and its
+// duration is the configured latency percentile.
+func aggregateSamples(samples []TestResult, unreachable int, endpoint config.Endpoint) TestResult {
+	durations := make([]time.Duration, 0, len(samples))
+	representative := samples[len(samples)-1]
+	failedSeen := false
+	successes := 0
+	for _, sample := range samples {
+		durations = append(durations, sample.Duration)
+		if sample.Passed {
+			successes++
+		} else if !failedSeen {
+			representative = sample
+			failedSeen = true
+		}
+	}
+
+	stats := NewLatencyStats(durations)
+	stats.Samples += unreachable
+	stats.Successes = successes
+
+	result := representative
+	result.Duration, _ = stats.Percentile(endpoint.LatencyPercentile)
+	result.Passed = stats.Successes == stats.Samples
+	result.Stats = &stats
+	if unreachable > 0 && result.Message == "" && !failedSeen {
+		result.Message = fmt.Sprintf("%d of %d samples were unreachable", unreachable, stats.Samples)
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func millis(values ...int) []time.Duration {
+	durations := make([]time.Duration, len(values))
+	for i, v := range values {
+		durations[i] = time.Duration(v) * time.Millisecond
+	}
+	return durations
+}
+
+func TestNewLatencyStats(t *testing.T) {
+	durations := millis(10, 20, 30, 40, 50, 60, 70, 80, 90, 100)
+	stats := NewLatencyStats(durations)
+
+	assert.Equal(t, 10, stats.Samples)
+	assert.Equal(t, 10*time.Millisecond, stats.Min)
+	assert.Equal(t, 100*time.Millisecond, stats.Max)
+	assert.Equal(t, 55*time.Millisecond, stats.Mean)
+	assert.Equal(t, 50*time.Millisecond, stats.P50)
+	assert.Equal(t, 90*time.Millisecond, stats.P90)
+	assert.Equal(t, 100*time.Millisecond, stats.P99)
+	assert.Equal(t, millis(10, 20, 30, 40, 50, 60, 70, 80, 90, 100), durations, "input should not be reordered")
+
+	empty := NewLatencyStats(nil)
+	assert.Zero(t, empty.Samples)
+	assert.Zero(t, empty.SuccessRatio())
+}
+
+func TestLatencyStats_Percentile(t *testing.T) {
+	stats := NewLatencyStats(millis(10, 20, 30))
+	tests := map[string]time.Duration{
+		"":     30 * time.Millisecond,
+		"min":  10 * time.Millisecond,
+		"mean": 20 * time.Millisecond,
+		"p50":  20 * time.Millisecond,
+		"p90":  30 * time.Millisecond,
+		"p99":  30 * time.Millisecond,
+		"max":  30 * time.Millisecond,
+	}
+	for name, expected := range tests {
+		actual, err := stats.Percentile(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, actual, name)
+	}
+
+	_, err := stats.Percentile("p75")
+	assert.ErrorContains(t, err, "unknown latency-percentile 'p75'")
+}
+
+func TestAggregateSamples(t *testing.T) {
+	samples := []TestResult{
+		{Target: "https://example.com/a", Duration: 10 * time.Millisecond, HttpStatus: 200, ExpectedStatus: 200, Passed: true},
+		{Target: "https://example.com/a", Duration: 30 * time.Millisecond, HttpStatus: 503, ExpectedStatus: 200},
+		{Target: "https://example.com/a", Duration: 20 * time.Millisecond, HttpStatus: 200, ExpectedStatus: 200, Passed: true},
+	}
+
+	result := aggregateSamples(samples, 1, config.Endpoint{LatencyPercentile: "p50"})
+	require.NotNil(t, result.Stats)
+	assert.False(t, result.Passed)
+	assert.Equal(t, 503, result.HttpStatus, "first failing sample should be reported")
+	assert.Equal(t, 20*time.Millisecond, result.Duration)
+	assert.Equal(t, 4, result.Stats.Samples)
+	assert.Equal(t, 2, result.Stats.Successes)
+	assert.InDelta(t, 0.5, result.Stats.SuccessRatio(), 0.001)
+
+	passing := aggregateSamples([]TestResult{samples[0], samples[2]}, 1, config.Endpoint{})
+	assert.False(t, passing.Passed)
+	assert.Equal(t, "1 of 3 samples were unreachable", passing.Message)
+}
+
+func TestExecute_Samples(t *testing.T) {
+	ctx, _ := newContextWithLogger(t)
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := hits.Add(1)
+		if r.URL.Path == "/flaky" && count%2 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	suite := newMockConfig(server.URL, []config.Endpoint{
+		{Path: "/users", ExpectedStatus: 200},
+		{Path: "/health", ExpectedStatus: 200, Samples: 1},
+	})
+	suite.Repeat = 5
+
+	report, err := Execute(ctx, suite, false)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, int32(6), hits.Load())
+
+	require.NotNil(t, report.Results[0].Stats)
+	assert.Equal(t, 5, report.Results[0].Stats.Samples)
+	assert.Equal(t, 5, report.Results[0].Stats.Successes)
+	assert.True(t, report.Results[0].Passed)
+	assert.Nil(t, report.Results[1].Stats, "single samples are not aggregated")
+	assert.NoError(t, report.SummarizeResults())
+}
+
+func TestExecute_SamplesMixedOutcomes(t *testing.T) {
+	ctx, _ := newContextWithLogger(t)
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1)%2 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	suite := newMockConfig(server.URL, []config.Endpoint{
+		{Path: "/flaky", ExpectedStatus: 200, Samples: 4},
+	})
+
+	report, err := Execute(ctx, suite, false)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.False(t, report.Results[0].Passed)
+	assert.Equal(t, 503, report.Results[0].HttpStatus)
+	assert.Equal(t, 2, report.Results[0].Stats.Successes)
+}
+
+func TestExecute_InvalidLatencyPercentile(t *testing.T) {
+	ctx, _ := newContextWithLogger(t)
+	suite := newMockConfig("https://example.com", []config.Endpoint{
+		{Path: "/users", ExpectedStatus: 200, LatencyPercentile: "p42"},
+	})
+
+	_, err := Execute(ctx, suite, false)
+	assert.ErrorContains(t, err, "unknown latency-percentile 'p42'")
+}
+
+func TestExecute_ManyUnreachableSamples(t *testing.T) {
+	ctx, _ := newContextWithLogger(t)
+	suite := newMockConfig("invalid-url", []config.Endpoint{
+		{Path: "/users", ExpectedStatus: 200},
+	})
+	suite.Repeat = 25
+
+	report, err := Execute(ctx, suite, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Results)
+}
@@ -0,0 +1,127 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// maxBannerRead is the maximum number of bytes read while waiting for the
+// expected banner of a TCP check.
+const maxBannerRead int = 64 << 10
+
+// tcpCheck connects to a TCP port and optionally exchanges a banner.
+type tcpCheck struct{}
+
+func (tcpCheck) Validate(endpoint config.Endpoint) error {
+	if endpoint.TCP == nil || endpoint.TCP.Address == "" {
+		return errors.New("tcp.address is required")
+	}
+	if _, _, err := net.SplitHostPort(endpoint.TCP.Address); err != nil {
+		return fmt.Errorf("invalid tcp.address: %w", err)
+	}
+	return nil
+}
+
+func (tcpCheck) Target(baseURL string, endpoint config.Endpoint) string {
+	if endpoint.TCP == nil {
+		return "tcp://"
+	}
+	return "tcp://" + endpoint.TCP.Address
+}
+
+// Execute connects to the address of the endpoint. A connection that cannot
+// be established leaves the endpoint unreachable; a banner that does not
+// arrive fails it.
+func (tcpCheck) Execute(ctx context.Context, req CheckRequest) (TestResult, error) {
+	conf := req.Endpoint.TCP
+	ctx, cancel := req.withTimeout(ctx)
+	defer cancel()
+
+	dialer := net.Dialer{}
+	if conf.ConnectTimeout != nil {
+		dialer.Timeout = time.Duration(*conf.ConnectTimeout) * time.Millisecond
+	}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", conf.Address)
+	if err != nil {
+		return TestResult{}, err
+	}
+	defer conn.Close()
+	connected := time.Now()
+
+	result := TestResult{
+		Duration: connected.Sub(start),
+		Passed:   true,
+		Timings:  &PhaseTimings{Connect: connected.Sub(start)},
+	}
+	if conf.Send == "" && conf.Expect == "" {
+		return result, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if conf.Send != "" {
+		if _, err := conn.Write([]byte(conf.Send)); err != nil {
+			return TestResult{}, fmt.Errorf("error sending to %s: %w", conf.Address, err)
+		}
+	}
+	sent := time.Now()
+	if conf.Expect == "" {
+		result.Duration = sent.Sub(start)
+		return result, nil
+	}
+	received, firstByte, err := readBanner(conn, conf.Expect)
+	done := time.Now()
+
+	result.Duration = done.Sub(start)
+	result.Body = received
+	if !firstByte.IsZero() {
+		result.Timings.TimeToFirstByte = firstByte.Sub(sent)
+		result.Timings.Transfer = done.Sub(firstByte)
+	}
+	if !bytes.Contains(received, []byte(conf.Expect)) {
+		result.Passed = false
+		result.Message = fmt.Sprintf("expected %q but received %q", conf.Expect, received)
+		if err != nil && len(received) == 0 {
+			result.Message = fmt.Sprintf("expected %q but received nothing: %v", conf.Expect, err)
+		}
+	}
+	return result, nil
+}
+
+// readBanner reads from the connection until the expected text arrives, the
+// connection is closed or maxBannerRead bytes were read. It returns the time
+// of the first byte received, if any.
+func readBanner(conn net.Conn, expect string) ([]byte, time.Time, error) {
+	var received []byte
+	var firstByte time.Time
+	buf := make([]byte, 4096)
+	for len(received) < maxBannerRead {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if firstByte.IsZero() {
+				firstByte = time.Now()
+			}
+			received = append(received, buf[:n]...)
+			if bytes.Contains(received, []byte(expect)) {
+				return received, firstByte, nil
+			}
+		}
+		if err != nil {
+			return received, firstByte, err
+		}
+	}
+	return received, firstByte, nil
+}
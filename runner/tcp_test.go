@@ -0,0 +1,139 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedisStandIn listens on a local port, answering PING with +PONG and any
+// other line with an error, like a Redis server.
+func newRedisStandIn(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					if strings.TrimSpace(scanner.Text()) == "PING" {
+						_, _ = conn.Write([]byte("+PONG\r\n"))
+					} else {
+						_, _ = conn.Write([]byte("-ERR unknown command\r\n"))
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// closedAddress returns a local address nothing listens on.
+func closedAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return address
+}
+
+func TestRun_TCPCheck(t *testing.T) {
+	address := newRedisStandIn(t)
+	timeout := 500
+	tcpEndpoint := func(path string, check config.TCPCheck) config.Endpoint {
+		return config.Endpoint{Type: config.TypeTCP, Path: path, Timeout: &timeout, TCP: &check}
+	}
+	suite := &config.TestSuite{
+		Endpoints: []config.Endpoint{
+			tcpEndpoint("/connect", config.TCPCheck{Address: address}),
+			tcpEndpoint("/ping", config.TCPCheck{Address: address, Send: "PING\r\n", Expect: "+PONG"}),
+			tcpEndpoint("/wrong-answer", config.TCPCheck{Address: address, Send: "HELLO\r\n", Expect: "+PONG"}),
+			tcpEndpoint("/silent", config.TCPCheck{Address: address, Expect: "+PONG"}),
+			tcpEndpoint("/closed", config.TCPCheck{Address: closedAddress(t)}),
+		},
+	}
+
+	report, err := Run(context.Background(), suite, Options{})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 4)
+
+	connect := report.Results[0]
+	assert.True(t, connect.Passed)
+	assert.Equal(t, "tcp://"+address, connect.Target)
+	require.NotNil(t, connect.Timings)
+	assert.Positive(t, connect.Timings.Connect, "connect latency should be reported")
+
+	ping := report.Results[1]
+	assert.True(t, ping.Passed, ping.Message)
+	assert.Equal(t, "+PONG\r\n", string(ping.Body))
+	assert.GreaterOrEqual(t, ping.Duration, ping.Timings.Connect)
+
+	wrong := report.Results[2]
+	assert.False(t, wrong.Passed)
+	assert.Equal(t, `expected "+PONG" but received "-ERR unknown command\r\n"`, wrong.Message)
+
+	silent := report.Results[3]
+	assert.False(t, silent.Passed)
+	assert.Contains(t, silent.Message, `expected "+PONG" but received nothing`)
+	assert.Less(t, silent.Duration, 2*time.Second, "the endpoint timeout should bound the exchange")
+
+	require.Len(t, report.Unreachable, 1)
+	assert.Equal(t, "/closed", report.Unreachable[0].Path)
+}
+
+func TestRun_TCPCheckDefaultTimeout(t *testing.T) {
+	// The peer accepts connections but never answers.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	suite := &config.TestSuite{
+		Endpoints: []config.Endpoint{
+			{Type: config.TypeTCP, Path: "/redis", TCP: &config.TCPCheck{Address: listener.Addr().String(), Send: "PING\r\n", Expect: "+PONG"}},
+		},
+	}
+	report, err := Run(context.Background(), suite, Options{Timeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.False(t, report.Results[0].Passed)
+	assert.Contains(t, report.Results[0].Message, `expected "+PONG" but received nothing`)
+	assert.Less(t, report.Results[0].Duration, 2*time.Second, "the default timeout should bound the exchange")
+}
+
+func TestTCPCheck_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    *config.TCPCheck
+		expected string
+	}{
+		{name: "valid", check: &config.TCPCheck{Address: "redis:6379"}},
+		{name: "missing", check: nil, expected: "tcp.address is required"},
+		{name: "without port", check: &config.TCPCheck{Address: "redis"}, expected: "invalid tcp.address: address redis: missing port in address"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tcpCheck{}.Validate(config.Endpoint{Type: config.TypeTCP, TCP: tc.check})
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
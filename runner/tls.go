@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/outputs"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTransport builds the HTTP transport shared by all requests of a run,
+// applying the suite-level TLS settings if any are provided.
+func newTransport(conf *config.TLSConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if conf == nil {
+		return transport, nil
+	}
+	tlsConfig, err := buildTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// WarnInsecure prints a warning if the TLS settings of the suite, or of any
+// of its endpoints, disable verification.
+func WarnInsecure(suite *config.TestSuite) {
+	warnInsecure(suite.TLS)
+	for _, endpoint := range suite.Endpoints {
+		if endpoint.GRPC != nil && endpoint.GRPC.TLS != nil && endpoint.GRPC.TLS.InsecureSkipVerify {
+			outputs.PrintWarn("TLS certificate verification is DISABLED for %s (grpc.tls.insecure-skip-verify); responses cannot be trusted", endpoint.DisplayName())
+		}
+	}
+}
+
+// warnInsecure prints a warning if the TLS settings disable verification.
+func warnInsecure(conf *config.TLSConfig) {
+	if conf != nil && conf.InsecureSkipVerify {
+		outputs.PrintWarn("TLS certificate verification is DISABLED (insecure-skip-verify); responses cannot be trusted")
+	}
+}
+
+// buildTLSConfig converts the suite TLS settings into a crypto/tls config.
+func buildTLSConfig(conf *config.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         conf.ServerName,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+	}
+
+	if conf.MinVersion != "" {
+		version, ok := tlsVersions[conf.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS min-version '%s'", conf.MinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if conf.CAFile != "" {
+		data, err := os.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid certificates found in CA file %s", conf.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (conf.CertFile == "") != (conf.KeyFile == "") {
+		return nil, fmt.Errorf("both cert-file and key-file must be set for client certificates")
+	}
+	if conf.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
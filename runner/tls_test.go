@@ -0,0 +1,256 @@
+package runner
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCertificate bundles a generated certificate with its PEM encodings.
+type testCertificate struct {
+	Cert    *x509.Certificate
+	Key     *ecdsa.PrivateKey
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+func (tc testCertificate) TLSCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	cert, err := tls.X509KeyPair(tc.CertPEM, tc.KeyPEM)
+	require.NoError(t, err)
+	return cert
+}
+
+// newTestCertificate issues a certificate from the template, signed by the
+// parent or self-signed if the parent is nil.
+func newTestCertificate(t *testing.T, template *x509.Certificate, parent *testCertificate) testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template.SerialNumber = serial
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+	}
+	if template.NotAfter.IsZero() {
+		template.NotAfter = time.Now().Add(24 * time.Hour)
+	}
+
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.Cert, parent.Key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return testCertificate{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func newTestCA(t *testing.T) testCertificate {
+	t.Helper()
+	return newTestCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "smokesweep test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}, nil)
+}
+
+func newTestServerCertificate(t *testing.T, ca testCertificate, notAfter time.Time) testCertificate {
+	t.Helper()
+	return newTestCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, &ca)
+}
+
+func writeTestFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestBuildTLSConfig_Errors(t *testing.T) {
+	ca := newTestCA(t)
+	caFile := writeTestFile(t, "ca.pem", ca.CertPEM)
+
+	tests := []struct {
+		name        string
+		conf        *config.TLSConfig
+		expectedErr string
+	}{
+		{
+			name:        "unsupported min version",
+			conf:        &config.TLSConfig{MinVersion: "2.0"},
+			expectedErr: "unsupported TLS min-version",
+		},
+		{
+			name:        "missing CA file",
+			conf:        &config.TLSConfig{CAFile: "/non/existent/ca.pem"},
+			expectedErr: "error reading CA file",
+		},
+		{
+			name:        "CA file without certificates",
+			conf:        &config.TLSConfig{CAFile: writeTestFile(t, "empty.pem", []byte("not a cert"))},
+			expectedErr: "no valid certificates",
+		},
+		{
+			name:        "client cert without key",
+			conf:        &config.TLSConfig{CAFile: caFile, CertFile: caFile},
+			expectedErr: "both cert-file and key-file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildTLSConfig(tt.conf)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestBuildTLSConfig_Settings(t *testing.T) {
+	ca := newTestCA(t)
+	conf := &config.TLSConfig{
+		CAFile:     writeTestFile(t, "ca.pem", ca.CertPEM),
+		ServerName: "internal.example.com",
+		MinVersion: "1.3",
+	}
+
+	tlsConfig, err := buildTLSConfig(conf)
+	require.NoError(t, err)
+	assert.Equal(t, "internal.example.com", tlsConfig.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+}
+
+func TestExecute_TLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert := newTestServerCertificate(t, ca, time.Time{})
+	clientCert := newTestCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "smokesweep client"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &ca)
+
+	caFile := writeTestFile(t, "ca.pem", ca.CertPEM)
+	certFile := writeTestFile(t, "client.pem", clientCert.CertPEM)
+	keyFile := writeTestFile(t, "client-key.pem", clientCert.KeyPEM)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.Cert)
+
+	tests := []struct {
+		name          string
+		requireClient bool
+		tls           *config.TLSConfig
+		expectedCount int
+	}{
+		{
+			name:          "private CA is trusted with CA bundle",
+			tls:           &config.TLSConfig{CAFile: caFile},
+			expectedCount: 1,
+		},
+		{
+			name:          "private CA is rejected without CA bundle",
+			tls:           nil,
+			expectedCount: 0,
+		},
+		{
+			name:          "insecure mode skips verification",
+			tls:           &config.TLSConfig{InsecureSkipVerify: true},
+			expectedCount: 1,
+		},
+		{
+			name:          "mutual TLS with client certificate",
+			requireClient: true,
+			tls:           &config.TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+			expectedCount: 1,
+		},
+		{
+			name:          "mutual TLS without client certificate",
+			requireClient: true,
+			tls:           &config.TLSConfig{CAFile: caFile},
+			expectedCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := newContextWithLogger(t)
+
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert.TLSCertificate(t)}}
+			if tt.requireClient {
+				server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+				server.TLS.ClientCAs = clientCAs
+			}
+			server.StartTLS()
+			defer server.Close()
+
+			suite := newMockConfig(server.URL, []config.Endpoint{
+				{Path: "/health", ExpectedStatus: 200},
+			})
+			suite.TLS = tt.tls
+
+			report, err := Execute(ctx, suite, false)
+			require.NoError(t, err)
+			assert.Len(t, report.Results, tt.expectedCount)
+		})
+	}
+}
+
+func TestPingURL_TLS(t *testing.T) {
+	ctx, _ := newContextWithLogger(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caFile := writeTestFile(t, "ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	err := PingURL(ctx, server.URL, 5*time.Second, nil)
+	assert.ErrorContains(t, err, "failed to reach target")
+
+	err = PingURL(ctx, server.URL, 5*time.Second, &config.TLSConfig{CAFile: caFile})
+	assert.NoError(t, err)
+
+	err = PingURL(ctx, server.URL, 5*time.Second, &config.TLSConfig{MinVersion: "0.9"})
+	assert.ErrorContains(t, err, "error configuring TLS")
+}
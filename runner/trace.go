@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// PhaseTimings is the per-phase breakdown of a single HTTP request. Phases
+// that did not occur, such as DNS on a reused connection, are left at zero.
+type PhaseTimings struct {
+	// DNS is the time spent resolving the target hostname.
+	DNS time.Duration `json:"dns_ns"`
+
+	// Connect is the time spent establishing the TCP connection.
+	Connect time.Duration `json:"connect_ns"`
+
+	// TLS is the time spent on the TLS handshake.
+	TLS time.Duration `json:"tls_ns"`
+
+	// TimeToFirstByte is the time from sending the request to the first response byte.
+	TimeToFirstByte time.Duration `json:"ttfb_ns"`
+
+	// Transfer is the time spent reading the response body.
+	Transfer time.Duration `json:"transfer_ns"`
+
+	// Handshake is the time spent establishing a WebSocket connection.
+	Handshake time.Duration `json:"handshake_ns,omitempty"`
+
+	// FirstMessage is the time from the WebSocket handshake to the first
+	// received message.
+	FirstMessage time.Duration `json:"first_message_ns,omitempty"`
+}
+
+// traceRecorder collects the timestamps of each request phase.
+type traceRecorder struct {
+	mu           sync.Mutex
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+// clientTrace returns the httptrace hooks that feed the recorder.
+func (tr *traceRecorder) clientTrace() *httptrace.ClientTrace {
+	mark := func(field *time.Time, overwrite bool) {
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		if overwrite || field.IsZero() {
+			*field = time.Now()
+		}
+	}
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { mark(&tr.dnsStart, false) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { mark(&tr.dnsDone, true) },
+		ConnectStart:         func(string, string) { mark(&tr.connectStart, false) },
+		ConnectDone:          func(string, string, error) { mark(&tr.connectDone, true) },
+		TLSHandshakeStart:    func() { mark(&tr.tlsStart, false) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { mark(&tr.tlsDone, true) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { mark(&tr.wroteRequest, true) },
+		GotFirstResponseByte: func() { mark(&tr.firstByte, false) },
+	}
+}
+
+// timings computes the phase durations, given the time the body was fully read.
+func (tr *traceRecorder) timings(bodyDone time.Time) *PhaseTimings {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	between := func(start, end time.Time) time.Duration {
+		if start.IsZero() || end.IsZero() || end.Before(start) {
+			return 0
+		}
+		return end.Sub(start)
+	}
+	return &PhaseTimings{
+		DNS:             between(tr.dnsStart, tr.dnsDone),
+		Connect:         between(tr.connectStart, tr.connectDone),
+		TLS:             between(tr.tlsStart, tr.tlsDone),
+		TimeToFirstByte: between(tr.wroteRequest, tr.firstByte),
+		Transfer:        between(tr.firstByte, bodyDone),
+	}
+}
@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute_PhaseTimings(t *testing.T) {
+	tests := []struct {
+		name      string
+		newServer func(handler http.Handler) *httptest.Server
+		expectTLS bool
+	}{
+		{
+			name:      "plain HTTP",
+			newServer: httptest.NewServer,
+		},
+		{
+			name:      "HTTPS",
+			newServer: httptest.NewTLSServer,
+			expectTLS: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := newContextWithLogger(t)
+			server := tt.newServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(20 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+				time.Sleep(20 * time.Millisecond)
+				_, _ = w.Write([]byte("done"))
+			}))
+			defer server.Close()
+
+			suite := newMockConfig(server.URL, []config.Endpoint{
+				{Path: "/", ExpectedStatus: 200},
+			})
+			suite.TLS = &config.TLSConfig{InsecureSkipVerify: true}
+
+			report, err := Execute(ctx, suite, false)
+			require.NoError(t, err)
+			require.Len(t, report.Results, 1)
+
+			timings := report.Results[0].Timings
+			require.NotNil(t, timings)
+			assert.GreaterOrEqual(t, timings.TimeToFirstByte, 20*time.Millisecond)
+			assert.GreaterOrEqual(t, timings.Transfer, 20*time.Millisecond)
+			assert.Positive(t, timings.Connect)
+			if tt.expectTLS {
+				assert.Positive(t, timings.TLS)
+			} else {
+				assert.Zero(t, timings.TLS)
+			}
+			assert.GreaterOrEqual(t, report.Results[0].Duration, timings.TimeToFirstByte+timings.Transfer)
+		})
+	}
+}
+
+func TestTraceRecorder_MissingPhases(t *testing.T) {
+	recorder := &traceRecorder{}
+	timings := recorder.timings(time.Now())
+	assert.Equal(t, &PhaseTimings{}, timings)
+}
+
+func TestRun_EndlessBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		chunk := make([]byte, 32<<10)
+		for r.Context().Err() == nil {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	suite := newMockConfig(server.URL, []config.Endpoint{
+		{Path: "/feed", ExpectedStatus: 200},
+	})
+	report, err := Run(context.Background(), suite, Options{})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.True(t, report.Results[0].Passed)
+	assert.Len(t, report.Results[0].Body, int(MaxBodyCapture), "reading should stop once the capture is full")
+}
+
+func TestRun_DefaultTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+
+	suite := newMockConfig(server.URL, []config.Endpoint{
+		{Path: "/poll", ExpectedStatus: 200},
+	})
+	start := time.Now()
+	report, err := Run(context.Background(), suite, Options{Timeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	require.Len(t, report.Unreachable, 1, "a body that never ends should time out")
+	assert.Contains(t, report.Unreachable[0].Message, "deadline exceeded")
+
+	_, err = Run(context.Background(), suite, Options{Timeout: -time.Second})
+	assert.EqualError(t, err, "timeout must not be negative, got -1s")
+}
@@ -0,0 +1,197 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+// webSocketCheck performs a WebSocket handshake and optionally asserts on the
+// first received message.
+type webSocketCheck struct{}
+
+func (webSocketCheck) Validate(endpoint config.Endpoint) error {
+	conf := endpoint.WebSocket
+	if conf == nil {
+		return nil
+	}
+	if conf.Regex != "" {
+		if _, err := regexp.Compile(conf.Regex); err != nil {
+			return fmt.Errorf("invalid websocket.regex: %w", err)
+		}
+	}
+	if conf.JSONValue != nil && conf.JSONField == "" {
+		return errors.New("websocket.json-value requires websocket.json-field")
+	}
+	return nil
+}
+
+func (webSocketCheck) Target(baseURL string, endpoint config.Endpoint) string {
+	target := joinURL(baseURL, endpoint.Path)
+	switch {
+	case strings.HasPrefix(target, "https://"):
+		return "wss://" + strings.TrimPrefix(target, "https://")
+	case strings.HasPrefix(target, "http://"):
+		return "ws://" + strings.TrimPrefix(target, "http://")
+	default:
+		return target
+	}
+}
+
+// Execute connects to the target of the endpoint. A server that cannot be
+// reached leaves the endpoint unreachable; a rejected handshake, a missing
+// first message or a message failing the assertions fails it.
+func (webSocketCheck) Execute(ctx context.Context, req CheckRequest) (TestResult, error) {
+	conf := req.Endpoint.WebSocket
+	if conf == nil {
+		conf = &config.WebSocketCheck{}
+	}
+	// The deadline of the context bounds the handshake and the wait for the
+	// first message.
+	ctx, cancel := req.withTimeout(ctx)
+	defer cancel()
+
+	dialer := websocket.Dialer{Proxy: http.ProxyFromEnvironment}
+	if transport, ok := req.Client.Transport.(*http.Transport); ok {
+		dialer.TLSClientConfig = transport.TLSClientConfig
+		dialer.Proxy = transport.Proxy
+	}
+	header := http.Header{}
+	for key, value := range conf.Headers {
+		header.Set(key, value)
+	}
+
+	start := time.Now()
+	conn, resp, err := dialer.DialContext(ctx, req.Target, header)
+	handshake := time.Since(start)
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+			return TestResult{
+				Duration:       handshake,
+				ExpectedStatus: http.StatusSwitchingProtocols,
+				HttpStatus:     resp.StatusCode,
+				Message:        fmt.Sprintf("handshake rejected with HTTP %d", resp.StatusCode),
+			}, nil
+		}
+		return TestResult{}, err
+	}
+	defer conn.Close()
+
+	result := TestResult{
+		Duration:       handshake,
+		ExpectedStatus: http.StatusSwitchingProtocols,
+		HttpStatus:     resp.StatusCode,
+		Passed:         true,
+		Timings:        &PhaseTimings{Handshake: handshake},
+	}
+	if conf.Send == "" && conf.Contains == "" && conf.Regex == "" && conf.JSONField == "" {
+		return result, nil
+	}
+
+	if conf.Send != "" {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(conf.Send)); err != nil {
+			return TestResult{}, fmt.Errorf("error sending message: %w", err)
+		}
+	}
+	deadline, hasDeadline := ctx.Deadline()
+	if conf.MessageTimeout != nil {
+		messageDeadline := time.Now().Add(time.Duration(*conf.MessageTimeout) * time.Millisecond)
+		if !hasDeadline || messageDeadline.Before(deadline) {
+			deadline, hasDeadline = messageDeadline, true
+		}
+	}
+	if hasDeadline {
+		_ = conn.SetReadDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	_, message, err := conn.ReadMessage()
+	received := time.Now()
+	result.Duration = received.Sub(start)
+	if err != nil {
+		result.Passed = false
+		result.Message = fmt.Sprintf("no message received: %v", err)
+		return result, nil
+	}
+	result.Timings.FirstMessage = received.Sub(start) - handshake
+	result.Body = message
+	if failure := checkMessage(conf, message); failure != "" {
+		result.Passed = false
+		result.Message = failure
+	}
+	return result, nil
+}
+
+// checkMessage describes why a message fails the assertions of the check,
+// returning an empty string if it passes.
+func checkMessage(conf *config.WebSocketCheck, message []byte) string {
+	if conf.Contains != "" && !strings.Contains(string(message), conf.Contains) {
+		return fmt.Sprintf("first message does not contain %q", conf.Contains)
+	}
+	if conf.Regex != "" && !regexp.MustCompile(conf.Regex).Match(message) {
+		return fmt.Sprintf("first message does not match /%s/", conf.Regex)
+	}
+	if conf.JSONField == "" {
+		return ""
+	}
+	var document any
+	if err := json.Unmarshal(message, &document); err != nil {
+		return fmt.Sprintf("first message is not JSON: %v", err)
+	}
+	value, ok := jsonField(document, conf.JSONField)
+	if !ok {
+		return fmt.Sprintf("first message has no field %s", conf.JSONField)
+	}
+	if conf.JSONValue != nil && jsonText(value) != *conf.JSONValue {
+		return fmt.Sprintf("expected %s to be %q but got %q", conf.JSONField, *conf.JSONValue, jsonText(value))
+	}
+	return ""
+}
+
+// jsonField looks up a dot-separated path in a decoded JSON document. Numeric
+// segments index arrays.
+func jsonField(document any, path string) (any, bool) {
+	current := document
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// jsonText formats a JSON value for comparison: strings as is, anything else
+// as its JSON encoding.
+func jsonText(value any) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -0,0 +1,198 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWebSocketStandIn serves a realtime gateway stand-in: /echo echoes every
+// message, /welcome greets new connections with a JSON message, /silent
+// never writes and /private requires a token.
+func newWebSocketStandIn(t *testing.T) *httptest.Server {
+	t.Helper()
+	var upgrader websocket.Upgrader
+	upgrade := func(w http.ResponseWriter, r *http.Request) *websocket.Conn {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return nil
+		}
+		return conn
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		conn := upgrade(w, r)
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			kind, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			_ = conn.WriteMessage(kind, message)
+		}
+	})
+	mux.HandleFunc("/welcome", func(w http.ResponseWriter, r *http.Request) {
+		conn := upgrade(w, r)
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "welcome", "data": {"channels": ["orders", "alerts"], "ready": true}}`))
+		_, _, _ = conn.ReadMessage()
+	})
+	mux.HandleFunc("/silent", func(w http.ResponseWriter, r *http.Request) {
+		conn := upgrade(w, r)
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+		_, _, _ = conn.ReadMessage()
+	})
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn := upgrade(w, r)
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+		_, _, _ = conn.ReadMessage()
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRun_WebSocketCheck(t *testing.T) {
+	server := newWebSocketStandIn(t)
+	messageTimeout := 100
+	ready := "true"
+	wrongType := "goodbye"
+
+	tests := []struct {
+		name    string
+		path    string
+		check   *config.WebSocketCheck
+		passed  bool
+		message string
+	}{
+		{name: "handshake only", path: "/silent", passed: true},
+		{name: "echo contains", path: "/echo", check: &config.WebSocketCheck{Send: "ping", Contains: "ping"}, passed: true},
+		{name: "echo regex", path: "/echo", check: &config.WebSocketCheck{Send: "seq=42", Regex: `^seq=\d+$`}, passed: true},
+		{name: "regex mismatch", path: "/echo", check: &config.WebSocketCheck{Send: "seq=x", Regex: `^seq=\d+$`}, message: `first message does not match /^seq=\d+$/`},
+		{name: "json field", path: "/welcome", check: &config.WebSocketCheck{JSONField: "data.channels.1"}, passed: true},
+		{name: "json value", path: "/welcome", check: &config.WebSocketCheck{JSONField: "data.ready", JSONValue: &ready}, passed: true},
+		{name: "json value mismatch", path: "/welcome", check: &config.WebSocketCheck{JSONField: "type", JSONValue: &wrongType}, message: `expected type to be "goodbye" but got "welcome"`},
+		{name: "json field missing", path: "/welcome", check: &config.WebSocketCheck{JSONField: "data.users"}, message: "first message has no field data.users"},
+		{name: "headers", path: "/private", check: &config.WebSocketCheck{Headers: map[string]string{"Authorization": "Bearer secret"}}, passed: true},
+		{name: "handshake rejected", path: "/private", message: "handshake rejected with HTTP 401"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := &config.TestSuite{
+				URL:       server.URL,
+				Endpoints: []config.Endpoint{{Type: config.TypeWebSocket, Path: tc.path, WebSocket: tc.check}},
+			}
+			report, err := Run(context.Background(), suite, Options{})
+			require.NoError(t, err)
+			require.Len(t, report.Results, 1, "the server should be reachable")
+			result := report.Results[0]
+			assert.Equal(t, tc.passed, result.Passed, result.Message)
+			assert.Equal(t, tc.message, result.Message)
+			assert.Equal(t, "ws://"+strings.TrimPrefix(server.URL, "http://")+tc.path, result.Target)
+		})
+	}
+
+	t.Run("latencies", func(t *testing.T) {
+		suite := &config.TestSuite{
+			URL:       server.URL,
+			Endpoints: []config.Endpoint{{Type: config.TypeWebSocket, Path: "/echo", WebSocket: &config.WebSocketCheck{Send: "ping"}}},
+		}
+		report, err := Run(context.Background(), suite, Options{})
+		require.NoError(t, err)
+		require.Len(t, report.Results, 1)
+		timings := report.Results[0].Timings
+		require.NotNil(t, timings)
+		assert.Positive(t, timings.Handshake)
+		assert.Positive(t, timings.FirstMessage)
+		assert.Equal(t, "ping", string(report.Results[0].Body))
+	})
+
+	t.Run("message timeout", func(t *testing.T) {
+		suite := &config.TestSuite{
+			URL: server.URL,
+			Endpoints: []config.Endpoint{{
+				Type:      config.TypeWebSocket,
+				Path:      "/silent",
+				WebSocket: &config.WebSocketCheck{Contains: "hello", MessageTimeout: &messageTimeout},
+			}},
+		}
+		started := time.Now()
+		report, err := Run(context.Background(), suite, Options{})
+		require.NoError(t, err)
+		assert.Less(t, time.Since(started), 2*time.Second)
+		require.Len(t, report.Results, 1)
+		assert.False(t, report.Results[0].Passed)
+		assert.Contains(t, report.Results[0].Message, "no message received")
+	})
+
+	t.Run("default message timeout", func(t *testing.T) {
+		suite := &config.TestSuite{
+			URL:       server.URL,
+			Endpoints: []config.Endpoint{{Type: config.TypeWebSocket, Path: "/silent", WebSocket: &config.WebSocketCheck{Contains: "hello"}}},
+		}
+		started := time.Now()
+		report, err := Run(context.Background(), suite, Options{Timeout: 100 * time.Millisecond})
+		require.NoError(t, err)
+		assert.Less(t, time.Since(started), 2*time.Second)
+		require.Len(t, report.Results, 1)
+		assert.False(t, report.Results[0].Passed)
+		assert.Contains(t, report.Results[0].Message, "no message received")
+	})
+
+	t.Run("default handshake timeout", func(t *testing.T) {
+		// The peer accepts connections but never answers the upgrade request.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		suite := &config.TestSuite{
+			URL:       "http://" + listener.Addr().String(),
+			Endpoints: []config.Endpoint{{Type: config.TypeWebSocket, Path: "/live"}},
+		}
+		started := time.Now()
+		report, err := Run(context.Background(), suite, Options{Timeout: 100 * time.Millisecond})
+		require.NoError(t, err)
+		assert.Less(t, time.Since(started), 2*time.Second)
+		assert.Empty(t, report.Results)
+		require.Len(t, report.Unreachable, 1)
+	})
+}
+
+func TestWebSocketCheck_TargetAndValidate(t *testing.T) {
+	check := webSocketCheck{}
+	assert.Equal(t, "wss://example.com/live", check.Target("https://example.com", config.Endpoint{Path: "/live"}))
+	assert.Equal(t, "ws://localhost:8080/live", check.Target("http://localhost:8080/", config.Endpoint{Path: "live"}))
+
+	value := "x"
+	assert.NoError(t, check.Validate(config.Endpoint{}))
+	assert.ErrorContains(t, check.Validate(config.Endpoint{WebSocket: &config.WebSocketCheck{Regex: "("}}), "invalid websocket.regex")
+	assert.EqualError(t, check.Validate(config.Endpoint{WebSocket: &config.WebSocketCheck{JSONValue: &value}}), "websocket.json-value requires websocket.json-field")
+}
@@ -0,0 +1,314 @@
+// Package server provides an HTTP API for triggering smoke test runs
+// remotely and retrieving their reports.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jgfranco17/dev-tooling-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/jgfranco17/smokesweep/config"
+	"github.com/jgfranco17/smokesweep/metrics"
+	"github.com/jgfranco17/smokesweep/runner"
+)
+
+// RunStatus is the lifecycle state of a run.
+type RunStatus string
+
+const (
+	StatusQueued    RunStatus = "queued"
+	StatusRunning   RunStatus = "running"
+	StatusCompleted RunStatus = "completed"
+	StatusFailed    RunStatus = "failed"
+)
+
+// maxRetainedRuns bounds the number of finished runs kept in memory.
+const maxRetainedRuns int = 500
+
+var (
+	ErrSuiteNotFound = errors.New("suite not found")
+	ErrQueueFull     = errors.New("run queue is full")
+)
+
+// RunRequest is the body of a request to start a run.
+type RunRequest struct {
+	// Suite is the name of the suite to run.
+	Suite string `json:"suite"`
+
+	// Environment selects between suites sharing the same name.
+	Environment string `json:"environment,omitempty"`
+
+	// Tags restricts the run to endpoints with at least one of the tags.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Run is a single triggered execution of a suite.
+type Run struct {
+	ID          string             `json:"id"`
+	Suite       string             `json:"suite"`
+	Environment string             `json:"environment,omitempty"`
+	Tags        []string           `json:"tags,omitempty"`
+	Status      RunStatus          `json:"status"`
+	CreatedAt   time.Time          `json:"created_at"`
+	StartedAt   *time.Time         `json:"started_at,omitempty"`
+	FinishedAt  *time.Time         `json:"finished_at,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	Report      *runner.TestReport `json:"report,omitempty"`
+
+	suite *config.TestSuite
+}
+
+// SuiteInfo describes a loaded suite.
+type SuiteInfo struct {
+	Name        string `json:"name"`
+	Environment string `json:"environment,omitempty"`
+	URL         string `json:"url"`
+	Endpoints   int    `json:"endpoints"`
+}
+
+// Server executes runs of the loaded suites in the background, queueing at
+// most a fixed number of pending runs.
+type Server struct {
+	suites     []*config.TestSuite
+	collectors []*metrics.Collector
+	queue      chan *Run
+	workers    int
+
+	mu    sync.RWMutex
+	runs  map[string]*Run
+	order []string
+}
+
+// New creates a server for the suites with the given number of concurrent
+// runs and maximum queue length.
+func New(suites []*config.TestSuite, workers int, queueSize int) (*Server, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("workers must be at least 1, got %d", workers)
+	}
+	if queueSize < 1 {
+		return nil, fmt.Errorf("queue size must be at least 1, got %d", queueSize)
+	}
+	s := &Server{
+		suites:  suites,
+		queue:   make(chan *Run, queueSize),
+		workers: workers,
+		runs:    make(map[string]*Run),
+	}
+	for _, suite := range suites {
+		s.collectors = append(s.collectors, metrics.NewCollector(suite))
+	}
+	return s, nil
+}
+
+// Start launches the background workers, which stop when the context is
+// cancelled. The context must carry a logger.
+func (s *Server) Start(ctx context.Context) {
+	for i := 0; i < s.workers; i++ {
+		go s.worker(ctx)
+	}
+}
+
+// Handler returns the HTTP handler of the API.
+func (s *Server) Handler() http.Handler {
+	gatherers := prometheus.Gatherers{}
+	for _, collector := range s.collectors {
+		gatherers = append(gatherers, collector.Registry())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /suites", s.handleListSuites)
+	mux.HandleFunc("POST /runs", s.handleCreateRun)
+	mux.HandleFunc("GET /runs/{id}", s.handleGetRun)
+	mux.Handle("GET /metrics", promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// Submit queues a run of the requested suite.
+func (s *Server) Submit(req RunRequest) (Run, error) {
+	index := s.findSuite(req.Suite, req.Environment)
+	if index < 0 {
+		return Run{}, fmt.Errorf("%w: %s", ErrSuiteNotFound, req.Suite)
+	}
+	suite := s.suites[index]
+	run := &Run{
+		ID:          newRunID(),
+		Suite:       suite.Name,
+		Environment: suite.Environment,
+		Tags:        req.Tags,
+		Status:      StatusQueued,
+		CreatedAt:   time.Now(),
+		suite:       suite,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case s.queue <- run:
+	default:
+		return Run{}, ErrQueueFull
+	}
+	s.runs[run.ID] = run
+	s.order = append(s.order, run.ID)
+	s.evictFinishedRuns()
+	return *run, nil
+}
+
+// Get returns a snapshot of the run with the given ID.
+func (s *Server) Get(id string) (Run, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	run, ok := s.runs[id]
+	if !ok {
+		return Run{}, false
+	}
+	return *run, true
+}
+
+// Suites describes the loaded suites.
+func (s *Server) Suites() []SuiteInfo {
+	infos := make([]SuiteInfo, len(s.suites))
+	for i, suite := range s.suites {
+		infos[i] = SuiteInfo{
+			Name:        suite.Name,
+			Environment: suite.Environment,
+			URL:         suite.URL,
+			Endpoints:   len(suite.Endpoints),
+		}
+	}
+	return infos
+}
+
+func (s *Server) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case run := <-s.queue:
+			s.execute(ctx, run)
+		}
+	}
+}
+
+// execute runs the suite of a queued run and records its outcome.
+func (s *Server) execute(ctx context.Context, run *Run) {
+	logger := logging.FromContext(ctx)
+	started := time.Now()
+	s.mu.Lock()
+	run.Status = StatusRunning
+	run.StartedAt = &started
+	s.mu.Unlock()
+
+	logger.WithFields(logrus.Fields{
+		"run":   run.ID,
+		"suite": run.Suite,
+	}).Info("Starting run")
+	report, err := runner.Execute(ctx, run.suite.FilterTags(run.Tags), false)
+
+	finished := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run.FinishedAt = &finished
+	if err != nil {
+		run.Status = StatusFailed
+		run.Error = err.Error()
+		return
+	}
+	run.Status = StatusCompleted
+	run.Report = &report
+	s.collectors[s.findSuite(run.Suite, run.Environment)].Observe(report)
+}
+
+// findSuite returns the index of the suite with the name, or -1. An empty
+// environment matches the first suite with the name.
+func (s *Server) findSuite(name string, environment string) int {
+	for i, suite := range s.suites {
+		if suite.Name == name && (environment == "" || suite.Environment == environment) {
+			return i
+		}
+	}
+	return -1
+}
+
+// evictFinishedRuns drops the oldest finished runs beyond the retention
+// limit. Callers must hold the lock.
+func (s *Server) evictFinishedRuns() {
+	for len(s.order) > maxRetainedRuns {
+		evicted := false
+		for i, id := range s.order {
+			if status := s.runs[id].Status; status == StatusCompleted || status == StatusFailed {
+				delete(s.runs, id)
+				s.order = append(s.order[:i], s.order[i+1:]...)
+				evicted = true
+				break
+			}
+		}
+		if !evicted {
+			return
+		}
+	}
+}
+
+func (s *Server) handleListSuites(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Suites())
+}
+
+func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
+	var req RunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run request: %w", err))
+		return
+	}
+	if req.Suite == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run request: suite is required"))
+		return
+	}
+
+	run, err := s.Submit(req)
+	switch {
+	case errors.Is(err, ErrSuiteNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrQueueFull):
+		writeError(w, http.StatusServiceUnavailable, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		w.Header().Set("Location", "/runs/"+run.ID)
+		writeJSON(w, http.StatusAccepted, run)
+	}
+}
+
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.Get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run not found: %s", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func newRunID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/dev-tooling-go/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jgfranco17/smokesweep/config"
+)
+
+func newContextWithLogger(t *testing.T) context.Context {
+	t.Helper()
+	logger := logging.New(os.Stderr, logrus.WarnLevel)
+	ctx, cancel := context.WithCancel(logging.WithContext(context.Background(), logger))
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func newTestSuites(url string) []*config.TestSuite {
+	return []*config.TestSuite{
+		{
+			Name:        "checkout",
+			Environment: "staging",
+			URL:         url,
+			Endpoints: []config.Endpoint{
+				{Path: "/users", ExpectedStatus: 200, Tags: []string{"api"}},
+				{Path: "/health", ExpectedStatus: 200, Tags: []string{"infra"}},
+			},
+		},
+		{
+			Name:        "checkout",
+			Environment: "prod",
+			URL:         url,
+			Endpoints:   []config.Endpoint{{Path: "/health", ExpectedStatus: 200}},
+		},
+	}
+}
+
+func doRequest(t *testing.T, handler http.Handler, method string, path string, body string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+	var decoded map[string]any
+	if recorder.Header().Get("Content-Type") == "application/json" {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &decoded))
+	}
+	return recorder, decoded
+}
+
+func TestNew_Validation(t *testing.T) {
+	_, err := New(nil, 0, 1)
+	assert.ErrorContains(t, err, "workers must be at least 1")
+	_, err = New(nil, 1, 0)
+	assert.ErrorContains(t, err, "queue size must be at least 1")
+}
+
+func TestServer_ListSuites(t *testing.T) {
+	s, err := New(newTestSuites("https://example.com"), 1, 1)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/suites", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var suites []SuiteInfo
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &suites))
+	assert.Equal(t, []SuiteInfo{
+		{Name: "checkout", Environment: "staging", URL: "https://example.com", Endpoints: 2},
+		{Name: "checkout", Environment: "prod", URL: "https://example.com", Endpoints: 1},
+	}, suites)
+}
+
+func TestServer_CreateRunErrors(t *testing.T) {
+	s, err := New(newTestSuites("https://example.com"), 1, 1)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "malformed body",
+			body:           "{",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid run request",
+		},
+		{
+			name:           "missing suite",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "suite is required",
+		},
+		{
+			name:           "unknown suite",
+			body:           `{"suite": "billing"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "suite not found",
+		},
+		{
+			name:           "unknown environment",
+			body:           `{"suite": "checkout", "environment": "dev"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "suite not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, body := doRequest(t, s.Handler(), http.MethodPost, "/runs", tt.body)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Contains(t, body["error"], tt.expectedError)
+		})
+	}
+}
+
+func TestServer_RunLifecycle(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	s, err := New(newTestSuites(target.URL), 1, 5)
+	require.NoError(t, err)
+	s.Start(newContextWithLogger(t))
+	handler := s.Handler()
+
+	recorder, body := doRequest(t, handler, http.MethodPost, "/runs", `{"suite": "checkout", "environment": "staging", "tags": ["api"]}`)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	id, ok := body["id"].(string)
+	require.True(t, ok)
+	assert.Equal(t, "/runs/"+id, recorder.Header().Get("Location"))
+
+	var run Run
+	require.Eventually(t, func() bool {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/runs/"+id, nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &run))
+		return run.Status == StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "checkout", run.Suite)
+	assert.Equal(t, "staging", run.Environment)
+	require.NotNil(t, run.Report)
+	require.Len(t, run.Report.Results, 1, "tag filter should select a single endpoint")
+	assert.Equal(t, "/users", run.Report.Results[0].Path)
+	assert.NotNil(t, run.StartedAt)
+	assert.NotNil(t, run.FinishedAt)
+
+	recorder, _ = doRequest(t, handler, http.MethodGet, "/metrics", "")
+	assert.Contains(t, recorder.Body.String(), `smokesweep_probe_success{endpoint="/users",environment="staging",suite="checkout",tags="api"} 1`)
+}
+
+func TestServer_GetUnknownRun(t *testing.T) {
+	s, err := New(newTestSuites("https://example.com"), 1, 1)
+	require.NoError(t, err)
+
+	recorder, body := doRequest(t, s.Handler(), http.MethodGet, "/runs/unknown", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Contains(t, body["error"], "run not found")
+}
+
+func TestServer_QueueFull(t *testing.T) {
+	s, err := New(newTestSuites("https://example.com"), 1, 1)
+	require.NoError(t, err)
+
+	// Workers are not started, so the first run occupies the queue.
+	recorder, _ := doRequest(t, s.Handler(), http.MethodPost, "/runs", `{"suite": "checkout"}`)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+
+	recorder, body := doRequest(t, s.Handler(), http.MethodPost, "/runs", `{"suite": "checkout"}`)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, body["error"], "run queue is full")
+}